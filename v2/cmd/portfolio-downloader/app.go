@@ -2,37 +2,101 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/deadletter"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/message"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/traderepublic/auth"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/transaction"
+	gocache "github.com/patrickmn/go-cache"
 )
 
 type App struct {
 	authClient         *auth.Client
+	refresher          *auth.Refresher
 	credentialsService auth.CredentialsServiceInterface
 	messageClient      message.ClientInterface
-	eventBus           *bus.EventBus
+	eventBus           bus.EventBusInterface
 }
 
 func NewApp(
 	authClient *auth.Client,
+	refresher *auth.Refresher,
 	credentialsService auth.CredentialsServiceInterface,
 	messageClient message.ClientInterface,
-	eventBus *bus.EventBus,
+	eventBus bus.EventBusInterface,
 ) App {
 	return App{
 		authClient:         authClient,
+		refresher:          refresher,
 		credentialsService: credentialsService,
 		messageClient:      messageClient,
 		eventBus:           eventBus,
 	}
 }
 
-func (a *App) Run() error {
+// Run logs in and downloads the transactions, the subscriptions stop waiting once the context is done.
+func (a *App) Run(ctx context.Context) error {
+	err := a.login()
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Starting downloading transactions")
+
+	err = a.messageClient.SubscribeToTimelineTransactions(ctx)
+	if err != nil {
+		return fmt.Errorf("subscription failed: %w", err)
+	}
+
+	return nil
+}
+
+// RetryFailed replays the events kept by the dead-letter store through the pipeline, events failing
+// again are stored anew. The entries are left in the store until the rows of their transactions are written.
+func (a *App) RetryFailed(deadLetters *deadletter.Store, eventTypes *gocache.Cache) error {
+	entries, err := deadLetters.List()
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		slog.Info("No failed transactions to retry")
+
+		return nil
+	}
+
+	// Instruments of the transactions are fetched again
+	err = a.login()
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Retrying failed transactions", "count", len(entries))
+
+	for _, entry := range entries {
+		if eventType, found := entry.Attributes[transaction.AttributeEventType]; found {
+			eventTypes.Set(entry.ID, eventType, gocache.NoExpiration)
+		}
+
+		a.eventBus.Publish(entry.Event())
+	}
+
+	return nil
+}
+
+// login loads the stored credentials, logging in again when there are none or they have expired.
+func (a *App) login() error {
 	err := a.credentialsService.Load()
+
+	// Logging in again would replace the stored credentials with ones encrypted by the mistyped passphrase
+	if errors.Is(err, auth.ErrWrongPassphrase) {
+		return fmt.Errorf("could not load credentials: %w", err)
+	}
+
 	if err != nil {
 		slog.Warn("Failed to load credentials, need to authenticate", "error", err)
 
@@ -40,13 +104,33 @@ func (a *App) Run() error {
 		if err != nil {
 			return fmt.Errorf("authentication failed: %w", err)
 		}
+	} else {
+		err := a.validateCredentials()
+		if err != nil {
+			return err
+		}
 	}
 
-	slog.Info("Starting downloading transactions")
+	return nil
+}
+
+// validateCredentials renews the stored session to make sure it is still valid, expired credentials
+// are replaced by logging in again.
+func (a *App) validateCredentials() error {
+	_, err := a.refresher.Refresh()
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, auth.ErrSessionExpired) && !errors.Is(err, auth.ErrNoRefreshToken) {
+		return fmt.Errorf("could not validate credentials: %w", err)
+	}
 
-	err = a.messageClient.SubscribeToTimelineTransactions(context.Background())
+	slog.Warn("Stored credentials have expired, please log in again", "error", err)
+
+	err = a.authenticate()
 	if err != nil {
-		return fmt.Errorf("subscription failed: %w", err)
+		return fmt.Errorf("authentication failed: %w", err)
 	}
 
 	return nil
@@ -1,5 +1,29 @@
 package main
 
+import "time"
+
+// RetryFailedCmd replays the events kept by the dead-letter store of the profile.
+type RetryFailedCmd struct{}
+
 type Args struct {
-	DebugMode bool `arg:"--debug" help:"enable debug mode"`
+	RetryFailed     *RetryFailedCmd `arg:"subcommand:retry-failed" help:"replay the transactions that failed processing through the pipeline"`
+	DebugMode       bool            `arg:"--debug" help:"enable debug mode"`
+	Rules           string          `arg:"--rules" help:"path to a YAML file overriding the transaction type rules"`
+	Locale          string          `arg:"--locale" default:"en" help:"language of the responses: en, de, fr, it or es"`
+	Store           string          `arg:"--credentials-store" default:"file" help:"where credentials are kept: file, encrypted or keyring"`
+	Profile         string          `arg:"--profile" help:"name of the account profile to download, files are kept under ./profiles/<name>, the name default is reserved"`
+	AllProfiles     bool            `arg:"--all-profiles" help:"download all profiles one after another"`
+	ListProfiles    bool            `arg:"--list-profiles" help:"list the profiles and exit"`
+	NonInteractive  bool            `arg:"--non-interactive" help:"log in without prompting, phone number and PIN are read from TR_PHONE_NUMBER and TR_PIN"`
+	CredentialsFile string          `arg:"--credentials-file" help:"file with TR_PHONE_NUMBER and TR_PIN used instead of the environment in non-interactive mode"`
+	OTPSource       string          `arg:"--otp-source" default:"stdin" help:"where the 2FA code is read from in non-interactive mode: stdin, pipe:<path> or http:<address>"`
+	OTPTimeout      time.Duration   `arg:"--otp-timeout" default:"5m" help:"how long the 2FA code is waited for in non-interactive mode"`
+	AppTimeout      time.Duration   `arg:"--app-confirmation-timeout" default:"2m" help:"how long a login confirmed in the mobile app is waited for"`
+	MaxInFlight     int             `arg:"--max-in-flight" default:"10" help:"maximum number of subscriptions waiting for data at once, 0 for no limit"`
+	RequestRate     float64         `arg:"--requests-per-second" default:"5" help:"maximum number of subscriptions sent per second, 0 for no limit"`
+	Jitter          time.Duration   `arg:"--jitter" default:"100ms" help:"maximum random delay added to every subscription"`
+	SubTimeout      time.Duration   `arg:"--subscription-timeout" default:"20s" help:"how long the data of a subscription is waited for, 0 to wait forever"`
+	BusWorkers      int             `arg:"--bus-workers" help:"workers handling the events of each subscription, 0 handles every event in its own goroutine"`
+	BusQueueSize    int             `arg:"--bus-queue-size" default:"100" help:"events queued per subscription before publishing blocks, used with --bus-workers"`
+	FullSync        bool            `arg:"--full-sync" help:"download all transactions again instead of only the ones new or changed since the last run"`
 }
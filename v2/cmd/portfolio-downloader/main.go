@@ -2,17 +2,24 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/alexflint/go-arg"
-	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/checkpoint"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/console"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/deadletter"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/file"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/instrument"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/message"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/profile"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/syncstate"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/timelinedetails"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/timelinetransactions"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/traderepublic/api"
@@ -24,10 +31,24 @@ import (
 	gocache "github.com/patrickmn/go-cache"
 )
 
+const (
+	// limiterReportInterval is how often the subscription queue is logged in debug mode.
+	limiterReportInterval = 5 * time.Second
+
+	// drainTimeout is how long in-flight work is waited for after a run is interrupted.
+	drainTimeout = 10 * time.Second
+)
+
 func main() {
-	ctx, cancel := context.WithCancel(context.Background())
+	// Runs are cancelled on SIGINT and SIGTERM, a second signal terminates at once
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 
-	defer cancel()
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
 
 	var args Args
 
@@ -48,49 +69,335 @@ func main() {
 
 	slog.SetDefault(log)
 
-	credentialsService := auth.NewFileCredentialsService("")
+	if args.ListProfiles {
+		listProfiles()
 
-	apiClient, err := api.NewClient()
+		return
+	}
+
+	profiles, err := selectProfiles(args)
 	if err != nil {
-		log.Error("Error creating API client", "error", err)
+		log.Error("Error selecting profiles", "error", err)
 
 		return
 	}
 
-	wHandler := file.NewRawResponseHandler(writer.NewResponseWriter())
-	eventBus := bus.New()
+	locale, err := traderepublic.ParseLocale(args.Locale)
+	if err != nil {
+		log.Error("Error parsing locale", "error", err)
+
+		return
+	}
+
+	rules, err := transaction.LoadRules(args.Rules)
+	if err != nil {
+		log.Error("Error loading transaction rules", "error", err)
+
+		return
+	}
+
+	// Profiles are downloaded one after another
+	for _, prof := range profiles {
+		log.Info("Running profile", "profile", prof)
+
+		err = run(ctx, args, prof, locale, rules)
+		if err != nil {
+			log.Error("Error running profile", "profile", prof, "error", err)
+		}
+
+		if ctx.Err() != nil {
+			log.Warn("Interrupted, remaining profiles are skipped")
+
+			return
+		}
+	}
+}
+
+// run downloads the transactions of a single profile.
+func run(ctx context.Context, args Args, prof profile.Profile, locale traderepublic.Locale, rules *transaction.RuleSet) error {
+	// Connection and session refresh of the profile end with the run
+	ctx, cancel := context.WithCancel(ctx)
+
+	defer cancel()
+
+	err := prof.Init()
+	if err != nil {
+		return err
+	}
+
+	checkpointStore, err := checkpoint.Load(prof.CheckpointFilename())
+	if err != nil {
+		return fmt.Errorf("could not load checkpoint: %w", err)
+	}
+
+	if checkpointStore.Resuming() {
+		slog.Info("Resuming interrupted run, transactions already written are skipped")
+	}
+
+	syncState, err := newSyncState(args, prof)
+	if err != nil {
+		return fmt.Errorf("could not load sync state: %w", err)
+	}
+
+	credentialsService, err := newCredentialsService(args.Store, prof)
+	if err != nil {
+		return fmt.Errorf("could not create credentials service: %w", err)
+	}
+
+	apiClient, err := api.NewClient()
+	if err != nil {
+		return fmt.Errorf("could not create API client: %w", err)
+	}
+
+	wHandler := file.NewRawResponseHandler(writer.NewResponseWriter(prof.ResponseBaseDir()))
+	eventBus, closeBus := newEventBus(args)
+
+	defer closeBus()
 
 	eventBus.Subscribe(bus.TopicTimelineTransactionsReceived, wHandler.Handle)
 	eventBus.Subscribe(bus.TopicTimelineDetailsV2Received, wHandler.Handle)
 	eventBus.Subscribe(bus.TopicInstrumentReceived, wHandler.Handle)
 
-	wsclient := traderepublic.NewWSClient(traderepublic.NewPublisher(), ctx)
+	wsclient := traderepublic.NewWSClient(traderepublic.NewPublisher(), ctx, locale)
+	if wsclient == nil {
+		return errors.New("could not create websocket client")
+	}
+
+	wsclient.SetSubscriptionTimeout(args.SubTimeout)
+
+	// Session is renewed periodically and whenever a subscription is rejected as unauthenticated
+	refreshTicker := auth.NewSessionRefreshTicker()
+	defer refreshTicker.Stop()
+
+	refresher := auth.NewRefresher(apiClient, credentialsService, refreshTicker.C)
+
+	wsclient.SetTokenRefresher(func() (string, error) {
+		token, err := refresher.Refresh()
+
+		return token.Session(), err
+	})
+
+	go refresher.Run(ctx)
 
 	cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
-	msgClient := message.NewClient(eventBus, credentialsService, wsclient)
-	ttHandler := timelinetransactions.NewHandler(eventBus, msgClient)
+	eventTypes := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
+	limiter := message.NewLimiter(message.LimiterConfig{
+		MaxInFlight:       args.MaxInFlight,
+		RequestsPerSecond: args.RequestRate,
+		Jitter:            args.Jitter,
+	})
+
+	reportTicker := time.NewTicker(limiterReportInterval)
+
+	defer reportTicker.Stop()
+
+	go limiter.Report(ctx, reportTicker.C)
+
+	msgClient := message.NewClient(eventBus, credentialsService, wsclient, limiter)
+	msgClient.SetKnownTransactions(syncState)
+
+	ttHandler := timelinetransactions.NewHandler(ctx, eventBus, msgClient, eventTypes, checkpointStore, syncState)
 	tdHandler := timelinedetails.NewHandler(eventBus)
-	instrHandler := instrument.NewHandler(msgClient, cache)
+	instrHandler := instrument.NewHandler(ctx, eventBus, msgClient, cache)
 
-	mapper := transaction.NewDataMapper(cache)
-	resolver := transaction.NewTypeResolver()
-	trnHandler := transaction.NewHandler(resolver, mapper, eventBus)
+	mapper := transaction.NewDataMapper(cache, locale)
+	resolver := transaction.NewTypeResolver(rules, locale)
+	trnHandler := transaction.NewHandler(resolver, mapper, eventBus, eventTypes)
 	csvWriter := file.NewCSVWriter()
-	csvHandler := file.NewCSVHandler(internal.CSVFilename, csvWriter)
+	csvHandler := file.NewCSVHandler(prof.CSVFilename(), csvWriter, eventBus, checkpointStore)
 
 	eventBus.Subscribe(bus.TopicTimelineTransactionsReceived, ttHandler.Handle)
 	eventBus.Subscribe(bus.TopicTimelineDetailsV2Received, tdHandler.Handle)
 	eventBus.Subscribe(bus.TopicInstrumentFetch, instrHandler.HandleFetch)
 	eventBus.Subscribe(bus.TopicInstrumentReceived, instrHandler.HandleReceived)
+	eventBus.Subscribe(bus.TopicSubscriptionFailed, instrHandler.HandleFailed)
+	eventBus.Subscribe(bus.TopicInstrumentCached, mapper.HandleInstrumentCached)
 	eventBus.Subscribe(bus.TopicTimelineDetailsV2Received, trnHandler.Handle)
 	eventBus.Subscribe(bus.TopicModelReady, csvHandler.Handle)
 
-	app := NewApp(auth.NewClient(console.NewInputHandler(), apiClient), credentialsService, msgClient, eventBus)
+	deadLetters := deadletter.NewStore(prof.DeadLetterBaseDir())
+	eventBus.SubscribeWithError(bus.TopicDeadLetter, deadLetters.Handle)
+	eventBus.Subscribe(bus.TopicRowWritten, deadLetters.HandleWritten)
+	eventBus.Subscribe(bus.TopicRowWritten, syncState.HandleDone)
+	eventBus.Subscribe(bus.TopicTransactionSkipped, syncState.HandleDone)
 
-	err = app.Run()
+	inputHandler, err := newInputHandler(args)
 	if err != nil {
-		log.Error("Error running app", "error", err)
+		return fmt.Errorf("could not create input handler: %w", err)
+	}
+
+	authClient := auth.NewClient(inputHandler, apiClient)
+	authClient.SetConfirmationPolling(auth.DefaultConfirmationPollInterval, args.AppTimeout)
+
+	app := NewApp(authClient, refresher, credentialsService, msgClient, eventBus)
+
+	retrying := args.RetryFailed != nil
+	if retrying {
+		err = app.RetryFailed(deadLetters, eventTypes)
+	} else {
+		err = app.Run(ctx)
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not run app: %w", err)
+	}
+
+	// Done once the pages, details, instruments and rows started by the run have drained
+	err = eventBus.Tracker().Wait(ctx)
+	completed := err == nil
+
+	if !completed {
+		slog.Warn("Run interrupted, waiting for in-flight work", "timeout", drainTimeout)
+
+		drainCtx, cancelDrain := context.WithTimeout(context.Background(), drainTimeout)
+
+		defer cancelDrain()
+
+		err = eventBus.Tracker().Wait(drainCtx)
+		if err != nil {
+			slog.Error("In-flight work not drained", "error", err)
+		}
+	}
+
+	eventBus.Tracker().LogSummary()
+
+	// Rows are written before the checkpoint, so the checkpoint never lists rows missing from the file
+	err = csvWriter.Flush()
+	if err != nil {
+		return fmt.Errorf("could not write transactions: %w", err)
+	}
+
+	// Transactions that failed before, retried or downloaded again, are resolved once their rows are written
+	err = deadLetters.RemoveWritten()
+	if err != nil {
+		return fmt.Errorf("could not remove resolved dead letters: %w", err)
+	}
+
+	// A retry does not finish the interrupted run being resumed
+	err = checkpointStore.Save(completed && !(retrying && checkpointStore.Resuming()))
+	if err != nil {
+		return fmt.Errorf("could not save checkpoint: %w", err)
+	}
+
+	if !completed {
+		return fmt.Errorf("run interrupted: %w", ctx.Err())
+	}
+
+	// Transactions of interrupted runs are left to the checkpoint, a retry does not sync anything new
+	if !retrying {
+		err = syncState.Save()
+		if err != nil {
+			return fmt.Errorf("could not save sync state: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// selectProfiles returns the profiles to download, all known profiles or the requested one.
+func selectProfiles(args Args) ([]profile.Profile, error) {
+	if args.AllProfiles {
+		profiles, err := profile.List()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(profiles) == 0 {
+			return nil, errors.New("no profiles found")
+		}
+
+		return profiles, nil
+	}
+
+	prof, err := profile.New(args.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return []profile.Profile{prof}, nil
+}
+
+// listProfiles prints the profiles that have been used before.
+func listProfiles() {
+	profiles, err := profile.List()
+	if err != nil {
+		slog.Error("Error listing profiles", "error", err)
+
+		return
+	}
+
+	for _, prof := range profiles {
+		fmt.Println(prof)
+	}
+}
+
+// newSyncState loads the transactions downloaded by previous runs of the profile, a full sync starts over
+// and downloads every transaction again.
+func newSyncState(args Args, prof profile.Profile) (*syncstate.Store, error) {
+	if args.FullSync {
+		return syncstate.New(prof.SyncStateFilename()), nil
+	}
+
+	syncState, err := syncstate.Load(prof.SyncStateFilename())
+	if err != nil {
+		return nil, err
+	}
+
+	if !syncState.LastSync().IsZero() {
+		slog.Info("Downloading transactions new or changed since the last run", "last_sync", syncState.LastSync())
+	}
+
+	return syncState, nil
+}
+
+// newEventBus creates the bus handling every event in its own goroutine or, with workers configured, the
+// bus with bounded queues per subscription. The returned function stops the workers.
+func newEventBus(args Args) (bus.EventBusInterface, func()) {
+	if args.BusWorkers < 1 {
+		return bus.New(), func() {}
+	}
+
+	poolBus := bus.NewPoolBus(bus.PoolConfig{
+		Workers:   args.BusWorkers,
+		QueueSize: args.BusQueueSize,
+	})
+
+	return poolBus, poolBus.Close
+}
+
+// newInputHandler creates the handler prompting for the login or, in non-interactive mode, the one
+// reading it from the environment, the credentials file and the OTP source.
+func newInputHandler(args Args) (console.InputHandlerInterface, error) {
+	if !args.NonInteractive {
+		return console.NewInputHandler(), nil
+	}
+
+	otpSource, err := console.NewOTPSource(args.OTPSource, os.Stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	return console.NewScriptedInputHandler(args.CredentialsFile, otpSource, args.OTPTimeout)
+}
+
+// newCredentialsService creates the credentials service of the profile for the selected store, the
+// passphrase of the encrypted store is read from AUTH_PASSPHRASE or prompted for.
+func newCredentialsService(store string, prof profile.Profile) (auth.CredentialsServiceInterface, error) {
+	switch store {
+	case "file":
+		return auth.NewFileCredentialsService(prof.AuthTokenFilename()), nil
+	case "encrypted":
+		return auth.NewEncryptedFileCredentialsService(prof.EncryptedAuthTokenFilename(), func() (string, error) {
+			passphrase := os.Getenv("AUTH_PASSPHRASE")
+			if passphrase != "" {
+				return passphrase, nil
+			}
+
+			return console.NewInputHandler().GetPassphrase()
+		}), nil
+	case "keyring":
+		return auth.NewKeyringCredentialsService(prof.KeyringUser()), nil
 	}
 
-	time.Sleep(time.Minute * 1)
+	return nil, fmt.Errorf("unknown credentials store: %s", store)
 }
@@ -5,5 +5,10 @@ const (
 	TopicTimelineDetailsV2Received    = "timeline_detail_v2_received"
 	TopicInstrumentFetch              = "instrument_fetch"
 	TopicInstrumentReceived           = "instrument_received"
+	TopicInstrumentCached             = "instrument_cached"
 	TopicModelReady                   = "model_ready"
+	TopicSubscriptionFailed           = "subscription_failed"
+	TopicDeadLetter                   = "dead_letter"
+	TopicRowWritten                   = "row_written"
+	TopicTransactionSkipped           = "transaction_skipped"
 )
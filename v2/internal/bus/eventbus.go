@@ -21,24 +21,53 @@ func NewEvent(topic, id string, data any) Event {
 	}
 }
 
+// FailedEvent is the data of a TopicDeadLetter event, the event that could not be processed and why.
+type FailedEvent struct {
+	Event      Event
+	Err        error
+	Attributes map[string]string // Context the event needs to be replayed, e.g. the event type of a transaction
+}
+
 type EventHandler func(Event)
 
+// ErrorHandler is a handler reporting its failure, the errors are collected by the tracker of the bus.
+type ErrorHandler func(Event) error
+
 type EventBusInterface interface {
 	Subscribe(string, EventHandler)
+	SubscribeWithError(string, ErrorHandler)
 	Publish(Event)
+	Tracker() *Tracker
 }
 
 type EventBus struct {
 	subscribers map[string][]EventHandler
+	tracker     *Tracker
 	mu          sync.RWMutex
 }
 
 func New() *EventBus {
 	return &EventBus{
 		subscribers: make(map[string][]EventHandler),
+		tracker:     NewTracker(),
 	}
 }
 
+// SubscribeWithError subscribes a handler whose errors are recorded by the tracker.
+func (b *EventBus) SubscribeWithError(topic string, handler ErrorHandler) {
+	b.Subscribe(topic, func(event Event) {
+		err := handler(event)
+		if err != nil {
+			b.tracker.RecordError(event, err)
+		}
+	})
+}
+
+// Tracker returns the tracker of the work of the bus, running handlers count as pending work.
+func (b *EventBus) Tracker() *Tracker {
+	return b.tracker
+}
+
 func (b *EventBus) Subscribe(topic string, handler EventHandler) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -52,7 +81,14 @@ func (b *EventBus) Publish(event Event) {
 
 	if handlers, found := b.subscribers[event.Topic]; found {
 		for _, handler := range handlers {
-			go handler(event)
+			// Added before returning, so the publisher can finish its own work right after
+			b.tracker.Add()
+
+			go func() {
+				defer b.tracker.Done()
+
+				handler(event)
+			}()
 		}
 	}
 
@@ -62,3 +62,29 @@ func (mr *MockEventBusInterfaceMockRecorder) Subscribe(arg0, arg1 any) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockEventBusInterface)(nil).Subscribe), arg0, arg1)
 }
+
+// SubscribeWithError mocks base method.
+func (m *MockEventBusInterface) SubscribeWithError(arg0 string, arg1 ErrorHandler) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SubscribeWithError", arg0, arg1)
+}
+
+// SubscribeWithError indicates an expected call of SubscribeWithError.
+func (mr *MockEventBusInterfaceMockRecorder) SubscribeWithError(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeWithError", reflect.TypeOf((*MockEventBusInterface)(nil).SubscribeWithError), arg0, arg1)
+}
+
+// Tracker mocks base method.
+func (m *MockEventBusInterface) Tracker() *Tracker {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tracker")
+	ret0, _ := ret[0].(*Tracker)
+	return ret0
+}
+
+// Tracker indicates an expected call of Tracker.
+func (mr *MockEventBusInterfaceMockRecorder) Tracker() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tracker", reflect.TypeOf((*MockEventBusInterface)(nil).Tracker))
+}
@@ -0,0 +1,192 @@
+package bus
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+)
+
+const (
+	DefaultPoolWorkers   = 4
+	DefaultPoolQueueSize = 100
+)
+
+// PoolConfig configures the workers of every subscription of a PoolBus.
+type PoolConfig struct {
+	Workers   int // Workers handling the events of a subscription, one keeps the events in order
+	QueueSize int // Events waiting per subscription before Publish blocks
+}
+
+// PoolBus implements EventBusInterface with a fixed number of workers per subscription fed by a bounded
+// queue, so a slow handler does not hold back the other handlers of its topic. Publish blocks while the
+// queue of a subscription is full, so topics must not publish to each other in a cycle. Panicking handlers
+// are recovered and recorded by the tracker like returned errors.
+type PoolBus struct {
+	config     PoolConfig
+	topics     map[string][]*subscriptionPool
+	tracker    *Tracker
+	closed     bool
+	done       chan struct{}  // Closed by Close, publishers waiting for a full queue give up
+	publishing sync.WaitGroup // Publishers that may still send to the queues
+	mu         sync.RWMutex
+}
+
+// subscriptionPool is the queue and the workers of a handler.
+type subscriptionPool struct {
+	queue   chan Event
+	handler ErrorHandler
+	workers sync.WaitGroup
+}
+
+// NewPoolBus creates a new PoolBus, values below one fall back to the defaults.
+func NewPoolBus(config PoolConfig) *PoolBus {
+	if config.Workers < 1 {
+		config.Workers = DefaultPoolWorkers
+	}
+
+	if config.QueueSize < 1 {
+		config.QueueSize = DefaultPoolQueueSize
+	}
+
+	return &PoolBus{
+		config:  config,
+		topics:  make(map[string][]*subscriptionPool),
+		tracker: NewTracker(),
+		done:    make(chan struct{}),
+	}
+}
+
+// Subscribe subscribes a handler to the topic, its workers are started right away.
+func (b *PoolBus) Subscribe(topic string, handler EventHandler) {
+	b.SubscribeWithError(topic, func(event Event) error {
+		handler(event)
+
+		return nil
+	})
+}
+
+// SubscribeWithError subscribes a handler whose errors are recorded by the tracker.
+func (b *PoolBus) SubscribeWithError(topic string, handler ErrorHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	pool := &subscriptionPool{
+		queue:   make(chan Event, b.config.QueueSize),
+		handler: handler,
+	}
+
+	for range b.config.Workers {
+		pool.workers.Add(1)
+
+		go b.work(pool)
+	}
+
+	b.topics[topic] = append(b.topics[topic], pool)
+}
+
+// Publish queues the event for the workers of every subscription of its topic, blocking while a queue is
+// full. Events not queued yet when the bus is closed are dropped.
+func (b *PoolBus) Publish(event Event) {
+	b.mu.RLock()
+
+	pools := b.topics[event.Topic]
+	if b.closed || len(pools) == 0 {
+		b.mu.RUnlock()
+
+		return
+	}
+
+	b.publishing.Add(1)
+	b.mu.RUnlock()
+
+	defer b.publishing.Done()
+
+	for _, pool := range pools {
+		// Added before queueing, so the publisher can finish its own work right after
+		b.tracker.Add()
+
+		if len(pool.queue) == cap(pool.queue) {
+			slog.Debug("event queue full, waiting", "topic", event.Topic, "id", event.ID)
+		}
+
+		select {
+		case pool.queue <- event:
+		case <-b.done:
+			slog.Warn("event bus closed, event dropped", "topic", event.Topic, "id", event.ID)
+
+			b.tracker.Done()
+
+			return
+		}
+	}
+
+	slog.Debug("event published", "topic", event.Topic, "id", event.ID)
+}
+
+// Tracker returns the tracker of the work of the bus, queued and handled events count as pending work.
+func (b *PoolBus) Tracker() *Tracker {
+	return b.tracker
+}
+
+// Close stops accepting events and waits for the workers to handle the queued ones.
+func (b *PoolBus) Close() {
+	b.mu.Lock()
+
+	if b.closed {
+		b.mu.Unlock()
+
+		return
+	}
+
+	b.closed = true
+	close(b.done)
+
+	b.mu.Unlock()
+
+	// Queues are only closed once no publisher can send to them anymore
+	b.publishing.Wait()
+
+	for _, pools := range b.topics {
+		for _, pool := range pools {
+			close(pool.queue)
+		}
+	}
+
+	for _, pools := range b.topics {
+		for _, pool := range pools {
+			pool.workers.Wait()
+		}
+	}
+}
+
+// work handles the events of the subscription until its queue is closed.
+func (b *PoolBus) work(pool *subscriptionPool) {
+	defer pool.workers.Done()
+
+	for event := range pool.queue {
+		err := b.handle(pool.handler, event)
+		if err != nil {
+			b.tracker.RecordError(event, err)
+		}
+
+		b.tracker.Done()
+	}
+}
+
+// handle runs the handler, turning a panic into an error.
+func (b *PoolBus) handle(handler ErrorHandler, event Event) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			slog.Error("handler panicked", "topic", event.Topic, "id", event.ID, "panic", recovered, "stack", string(debug.Stack()))
+
+			err = fmt.Errorf("handler panicked: %v", recovered)
+		}
+	}()
+
+	return handler(event)
+}
@@ -0,0 +1,191 @@
+package bus_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolBus_Order(t *testing.T) {
+	t.Parallel()
+
+	eventBus := bus.NewPoolBus(bus.PoolConfig{Workers: 1, QueueSize: 10})
+
+	var (
+		received []string
+		mu       sync.Mutex
+	)
+
+	eventBus.Subscribe("topic", func(event bus.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		received = append(received, event.ID)
+	})
+
+	expected := make([]string, 0, 50)
+
+	for i := range 50 {
+		expected = append(expected, strconv.Itoa(i))
+		eventBus.Publish(bus.NewEvent("topic", strconv.Itoa(i), nil))
+	}
+
+	require.NoError(t, eventBus.Tracker().Wait(context.Background()))
+	eventBus.Close()
+
+	assert.Equal(t, expected, received)
+}
+
+func TestPoolBus_Backpressure(t *testing.T) {
+	t.Parallel()
+
+	eventBus := bus.NewPoolBus(bus.PoolConfig{Workers: 1, QueueSize: 1})
+	release := make(chan struct{})
+
+	eventBus.Subscribe("topic", func(bus.Event) {
+		<-release
+	})
+
+	// The first event is taken by the worker, the second fills the queue
+	eventBus.Publish(bus.NewEvent("topic", "1", nil))
+	eventBus.Publish(bus.NewEvent("topic", "2", nil))
+
+	published := make(chan struct{})
+
+	go func() {
+		eventBus.Publish(bus.NewEvent("topic", "3", nil))
+		close(published)
+	}()
+
+	select {
+	case <-published:
+		require.FailNow(t, "publish did not block on a full queue")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	<-published
+
+	require.NoError(t, eventBus.Tracker().Wait(context.Background()))
+	eventBus.Close()
+}
+
+func TestPoolBus_Errors(t *testing.T) {
+	t.Parallel()
+
+	eventBus := bus.NewPoolBus(bus.PoolConfig{Workers: 2, QueueSize: 10})
+	errFailed := errors.New("failed")
+
+	eventBus.SubscribeWithError("fail", func(bus.Event) error {
+		return errFailed
+	})
+
+	eventBus.Subscribe("panic", func(bus.Event) {
+		panic("boom")
+	})
+
+	eventBus.Publish(bus.NewEvent("fail", "1", nil))
+	eventBus.Publish(bus.NewEvent("panic", "2", nil))
+	eventBus.Publish(bus.NewEvent("unknown", "3", nil))
+
+	require.NoError(t, eventBus.Tracker().Wait(context.Background()))
+	eventBus.Close()
+
+	handlerErrors := eventBus.Tracker().Errors()
+	require.Len(t, handlerErrors, 2)
+
+	byTopic := map[string]bus.HandlerError{}
+	for _, handlerErr := range handlerErrors {
+		byTopic[handlerErr.Topic] = handlerErr
+	}
+
+	assert.ErrorIs(t, byTopic["fail"].Err, errFailed)
+	assert.Equal(t, "1", byTopic["fail"].ID)
+	assert.ErrorContains(t, byTopic["panic"].Err, "boom")
+}
+
+func TestPoolBus_SlowHandler(t *testing.T) {
+	t.Parallel()
+
+	eventBus := bus.NewPoolBus(bus.PoolConfig{Workers: 1, QueueSize: 10})
+	release := make(chan struct{})
+	handled := make(chan string, 1)
+
+	eventBus.Subscribe("topic", func(bus.Event) {
+		<-release
+	})
+
+	eventBus.Subscribe("topic", func(event bus.Event) {
+		handled <- event.ID
+	})
+
+	eventBus.Publish(bus.NewEvent("topic", "1", nil))
+
+	// The other handler of the topic does not wait for the slow one
+	select {
+	case id := <-handled:
+		assert.Equal(t, "1", id)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "handler waited for the slow handler of its topic")
+	}
+
+	close(release)
+
+	require.NoError(t, eventBus.Tracker().Wait(context.Background()))
+	eventBus.Close()
+}
+
+func TestPoolBus_CloseWhilePublishing(t *testing.T) {
+	t.Parallel()
+
+	eventBus := bus.NewPoolBus(bus.PoolConfig{Workers: 1, QueueSize: 1})
+	release := make(chan struct{})
+
+	eventBus.Subscribe("topic", func(bus.Event) {
+		<-release
+	})
+
+	// The first event is taken by the worker, the second fills the queue and the third blocks
+	eventBus.Publish(bus.NewEvent("topic", "1", nil))
+	eventBus.Publish(bus.NewEvent("topic", "2", nil))
+
+	published := make(chan struct{})
+
+	go func() {
+		eventBus.Publish(bus.NewEvent("topic", "3", nil))
+		close(published)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	closed := make(chan struct{})
+
+	go func() {
+		eventBus.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "blocked publisher not released by close")
+	}
+
+	close(release)
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "close did not return")
+	}
+
+	// The dropped event is not counted as pending work
+	require.NoError(t, eventBus.Tracker().Wait(context.Background()))
+}
@@ -0,0 +1,157 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+)
+
+const (
+	// Kinds of work counted besides the subscription types.
+	WorkTransaction = "transaction"
+	WorkCSVRow      = "csv_row"
+
+	// Outcomes of the work.
+	OutcomeReceived = "received"
+	OutcomeFailed   = "failed"
+	OutcomeIgnored  = "ignored"
+	OutcomeCanceled = "canceled"
+	OutcomeMapped   = "mapped"
+	OutcomeWritten  = "written"
+)
+
+// HandlerError is the failure of a handler, returned or recovered from a panic.
+type HandlerError struct {
+	Topic string
+	ID    string
+	Err   error
+}
+
+// Tracker keeps track of the outstanding work of a run, so it is known when everything has drained.
+// Work that starts further work has to Add it before calling Done on itself.
+type Tracker struct {
+	pending int
+	drained chan struct{} // Closed whenever no work is pending
+	counts  map[string]map[string]int
+	errors  []HandlerError
+	mu      sync.Mutex
+}
+
+// NewTracker creates a new Tracker without pending work.
+func NewTracker() *Tracker {
+	drained := make(chan struct{})
+	close(drained)
+
+	return &Tracker{
+		drained: drained,
+		counts:  make(map[string]map[string]int),
+	}
+}
+
+// Add registers a unit of outstanding work.
+func (t *Tracker) Add() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.pending == 0 {
+		t.drained = make(chan struct{})
+	}
+
+	t.pending++
+}
+
+// Done marks a unit of work added before as finished.
+func (t *Tracker) Done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.pending == 0 {
+		slog.Error("work marked as done without being added")
+
+		return
+	}
+
+	t.pending--
+
+	if t.pending == 0 {
+		close(t.drained)
+	}
+}
+
+// Count records the outcome of a finished piece of work for the summary.
+func (t *Tracker) Count(kind, outcome string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.counts[kind] == nil {
+		t.counts[kind] = make(map[string]int)
+	}
+
+	t.counts[kind][outcome]++
+}
+
+// RecordError records the failure of a handler of the event for the report.
+func (t *Tracker) RecordError(event Event, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.errors = append(t.errors, HandlerError{Topic: event.Topic, ID: event.ID, Err: err})
+}
+
+// Errors returns the failures of the handlers in the order they were recorded.
+func (t *Tracker) Errors() []HandlerError {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return slices.Clone(t.errors)
+}
+
+// Wait blocks until no work is pending or the context is done.
+func (t *Tracker) Wait(ctx context.Context) error {
+	t.mu.Lock()
+	drained := t.drained
+	t.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("work still pending: %w", ctx.Err())
+	case <-drained:
+		return nil
+	}
+}
+
+// Summary returns the counts of the outcomes keyed by the kind of work.
+func (t *Tracker) Summary() map[string]map[string]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	summary := make(map[string]map[string]int, len(t.counts))
+
+	for kind, outcomes := range t.counts {
+		summary[kind] = maps.Clone(outcomes)
+	}
+
+	return summary
+}
+
+// LogSummary logs the counts of the outcomes, one line per kind of work.
+func (t *Tracker) LogSummary() {
+	summary := t.Summary()
+
+	for _, kind := range slices.Sorted(maps.Keys(summary)) {
+		attrs := []any{"work", kind}
+
+		for _, outcome := range slices.Sorted(maps.Keys(summary[kind])) {
+			attrs = append(attrs, outcome, summary[kind][outcome])
+		}
+
+		slog.Info("Run summary", attrs...)
+	}
+
+	for _, handlerErr := range t.Errors() {
+		slog.Error("Handler failed", "topic", handlerErr.Topic, "id", handlerErr.ID, "error", handlerErr.Err)
+	}
+}
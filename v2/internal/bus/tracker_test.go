@@ -0,0 +1,70 @@
+package bus_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_Wait(t *testing.T) {
+	t.Parallel()
+
+	tracker := bus.NewTracker()
+
+	require.NoError(t, tracker.Wait(context.Background()))
+
+	tracker.Add()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, tracker.Wait(ctx), context.DeadlineExceeded)
+
+	tracker.Done()
+
+	require.NoError(t, tracker.Wait(context.Background()))
+}
+
+func TestTracker_WaitsForHandlers(t *testing.T) {
+	t.Parallel()
+
+	eventBus := bus.New()
+	release := make(chan struct{})
+	handled := make(chan struct{})
+
+	// The first handler starts further work before returning
+	eventBus.Subscribe("first", func(event bus.Event) {
+		eventBus.Publish(bus.NewEvent("second", event.ID, nil))
+	})
+
+	eventBus.Subscribe("second", func(event bus.Event) {
+		<-release
+		eventBus.Tracker().Count("test", bus.OutcomeReceived)
+		close(handled)
+	})
+
+	eventBus.Publish(bus.NewEvent("first", "1", nil))
+
+	waited := make(chan error)
+
+	go func() {
+		waited <- eventBus.Tracker().Wait(context.Background())
+	}()
+
+	select {
+	case <-waited:
+		require.FailNow(t, "wait returned before the handlers finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+
+	require.NoError(t, <-waited)
+	<-handled
+
+	assert.Equal(t, map[string]map[string]int{"test": {bus.OutcomeReceived: 1}}, eventBus.Tracker().Summary())
+}
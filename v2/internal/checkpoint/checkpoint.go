@@ -0,0 +1,120 @@
+// Package checkpoint records how far a run got, so an interrupted run can be resumed.
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/writer"
+)
+
+// Checkpoint is the state stored after every run.
+type Checkpoint struct {
+	Completed bool      `json:"completed"`
+	StoppedAt time.Time `json:"stoppedAt"`
+	Processed []string  `json:"processed"` // IDs of the transactions written to the CSV file
+}
+
+// Store keeps the checkpoint of a profile, the transactions processed before an interrupted run are
+// skipped when resuming it.
+type Store struct {
+	filePath  string
+	resuming  bool
+	processed map[string]struct{}
+	mu        sync.RWMutex
+}
+
+// Load reads the checkpoint of the previous run, a missing file starts without one.
+func Load(filePath string) (*Store, error) {
+	store := &Store{
+		filePath:  filePath,
+		processed: make(map[string]struct{}),
+	}
+
+	contents, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' file: %w", filePath, err)
+	}
+
+	var previous Checkpoint
+
+	err = json.Unmarshal(contents, &previous)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse '%s' file: %w", filePath, err)
+	}
+
+	store.resuming = !previous.Completed
+
+	for _, id := range previous.Processed {
+		store.processed[id] = struct{}{}
+	}
+
+	return store, nil
+}
+
+// Resuming reports whether the previous run was interrupted.
+func (s *Store) Resuming() bool {
+	return s.resuming
+}
+
+// Skip reports whether the transaction was processed by the interrupted run being resumed.
+func (s *Store) Skip(id string) bool {
+	if !s.resuming {
+		return false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, found := s.processed[id]
+
+	return found
+}
+
+// MarkProcessed records the transaction as written.
+func (s *Store) MarkProcessed(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.processed[id] = struct{}{}
+}
+
+// Save writes the checkpoint, replacing the previous one at once so it is never left half written.
+func (s *Store) Save(completed bool) error {
+	s.mu.RLock()
+
+	checkpoint := Checkpoint{
+		Completed: completed,
+		StoppedAt: time.Now().UTC(),
+		Processed: make([]string, 0, len(s.processed)),
+	}
+
+	for id := range s.processed {
+		checkpoint.Processed = append(checkpoint.Processed, id)
+	}
+
+	s.mu.RUnlock()
+
+	slices.Sort(checkpoint.Processed)
+
+	contents, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	err = writer.WriteFileAtomic(s.filePath, contents)
+	if err != nil {
+		return fmt.Errorf("failed to write '%s' file: %w", s.filePath, err)
+	}
+
+	return nil
+}
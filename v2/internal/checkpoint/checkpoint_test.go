@@ -0,0 +1,68 @@
+package checkpoint_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/checkpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		completed bool
+		resuming  bool
+	}{
+		{
+			name:      "Interrupted run is resumed",
+			completed: false,
+			resuming:  true,
+		},
+		{
+			name:      "Completed run starts over",
+			completed: true,
+			resuming:  false,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			filePath := filepath.Join(t.TempDir(), "checkpoint.json")
+
+			store, err := checkpoint.Load(filePath)
+			require.NoError(t, err)
+			assert.False(t, store.Resuming())
+
+			store.MarkProcessed("b")
+			store.MarkProcessed("a")
+
+			err = store.Save(testCase.completed)
+			require.NoError(t, err)
+
+			store, err = checkpoint.Load(filePath)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.resuming, store.Resuming())
+			assert.Equal(t, testCase.resuming, store.Skip("a"))
+			assert.False(t, store.Skip("c"))
+		})
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	err := os.WriteFile(filePath, []byte("{"), 0o600)
+	require.NoError(t, err)
+
+	_, err = checkpoint.Load(filePath)
+	assert.Error(t, err)
+}
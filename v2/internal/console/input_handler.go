@@ -47,3 +47,13 @@ func (h InputHandler) GetOTP() (string, error) {
 
 	return string(otp), nil
 }
+
+// GetPassphrase prompts the user to enter the passphrase the credentials are encrypted with.
+func (h InputHandler) GetPassphrase() (string, error) {
+	passphrase, err := ReadPassword("Credentials passphrase")
+	if err != nil {
+		return "", fmt.Errorf("could not read passphrase: %w", err)
+	}
+
+	return string(passphrase), nil
+}
@@ -0,0 +1,29 @@
+//go:build unix
+
+package console
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// createFifo creates the named pipe unless it exists, reporting whether it was created.
+func createFifo(path string) (bool, error) {
+	err := syscall.Mkfifo(path, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// openFifo opens the named pipe for reading and writing, so opening does not block until a writer
+// appears and the read can be bounded by a deadline.
+func openFifo(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_RDWR, os.ModeNamedPipe)
+}
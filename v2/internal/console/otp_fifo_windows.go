@@ -0,0 +1,18 @@
+//go:build windows
+
+package console
+
+import (
+	"errors"
+	"os"
+)
+
+var errFifoUnsupported = errors.New("named pipes are not supported on windows")
+
+func createFifo(string) (bool, error) {
+	return false, errFifoUnsupported
+}
+
+func openFifo(string) (*os.File, error) {
+	return nil, errFifoUnsupported
+}
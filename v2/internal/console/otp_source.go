@@ -0,0 +1,215 @@
+package console
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// OTPSourceStdin reads the OTP as a single line from stdin.
+	OTPSourceStdin = "stdin"
+	// OTPSourcePipe reads the OTP as a single line from the named pipe following the prefix, e.g. pipe:/tmp/otp.
+	OTPSourcePipe = "pipe:"
+	// OTPSourceHTTP accepts the OTP on a local HTTP listener following the prefix, e.g. http:127.0.0.1:8099.
+	OTPSourceHTTP = "http:"
+
+	// OTPPath is the path the HTTP source accepts the OTP on, as the code parameter.
+	OTPPath = "/otp"
+)
+
+var (
+	ErrInvalidOTP       = errors.New("invalid OTP")
+	ErrUnknownOTPSource = errors.New("unknown OTP source")
+)
+
+// otpPattern matches the OTP, optionally given as OTP=<code> to match the format of .env files.
+var otpPattern = regexp.MustCompile(`^(?i:otp=)?(\d{4,8})$`)
+
+// OTPSource provides the one-time password when no terminal is attached.
+type OTPSource interface {
+	// ReadOTP waits for the OTP until the context is done.
+	ReadOTP(ctx context.Context) (string, error)
+}
+
+// NewOTPSource creates the OTP source described by spec: stdin, pipe:<path> or http:<address>.
+func NewOTPSource(spec string, stdin io.Reader) (OTPSource, error) {
+	switch {
+	case spec == OTPSourceStdin:
+		return NewStdinOTPSource(stdin), nil
+	case strings.HasPrefix(spec, OTPSourcePipe):
+		return NewPipeOTPSource(strings.TrimPrefix(spec, OTPSourcePipe)), nil
+	case strings.HasPrefix(spec, OTPSourceHTTP):
+		return NewHTTPOTPSource(strings.TrimPrefix(spec, OTPSourceHTTP)), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownOTPSource, spec)
+}
+
+// StdinOTPSource reads the OTP as a single line, so it can be piped in by a script.
+type StdinOTPSource struct {
+	reader *bufio.Reader
+}
+
+// NewStdinOTPSource creates a new StdinOTPSource reading from the given reader.
+func NewStdinOTPSource(reader io.Reader) *StdinOTPSource {
+	return &StdinOTPSource{
+		reader: bufio.NewReader(reader),
+	}
+}
+
+// ReadOTP reads the next line, the read itself cannot be interrupted and is left behind when the context is done.
+func (s *StdinOTPSource) ReadOTP(ctx context.Context) (string, error) {
+	slog.Info("Waiting for 2FA code on stdin")
+
+	return readLine(ctx, s.reader)
+}
+
+// PipeOTPSource reads the OTP as a single line written to a named pipe, e.g. echo 1234 > /tmp/otp.
+type PipeOTPSource struct {
+	path string
+}
+
+// NewPipeOTPSource creates a new PipeOTPSource, the pipe is created on first read if it does not exist.
+func NewPipeOTPSource(path string) *PipeOTPSource {
+	return &PipeOTPSource{
+		path: path,
+	}
+}
+
+// ReadOTP waits for a line to be written to the pipe.
+func (s *PipeOTPSource) ReadOTP(ctx context.Context) (string, error) {
+	created, err := createFifo(s.path)
+	if err != nil {
+		return "", fmt.Errorf("could not create named pipe '%s': %w", s.path, err)
+	}
+
+	if created {
+		defer os.Remove(s.path)
+	}
+
+	pipe, err := openFifo(s.path)
+	if err != nil {
+		return "", fmt.Errorf("could not open named pipe '%s': %w", s.path, err)
+	}
+
+	defer pipe.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = pipe.SetReadDeadline(deadline)
+	}
+
+	slog.Info("Waiting for 2FA code on named pipe", "path", s.path)
+
+	return readLine(ctx, bufio.NewReader(pipe))
+}
+
+// HTTPOTPSource accepts the OTP on a local HTTP listener, e.g. curl -d code=1234 http://127.0.0.1:8099/otp.
+type HTTPOTPSource struct {
+	addr  string
+	codes chan string
+}
+
+// NewHTTPOTPSource creates a new HTTPOTPSource listening on addr while waiting for the OTP.
+func NewHTTPOTPSource(addr string) *HTTPOTPSource {
+	return &HTTPOTPSource{
+		addr:  addr,
+		codes: make(chan string, 1),
+	}
+}
+
+// ReadOTP listens until a valid OTP is received.
+func (s *HTTPOTPSource) ReadOTP(ctx context.Context) (string, error) {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return "", fmt.Errorf("could not listen on '%s': %w", s.addr, err)
+	}
+
+	server := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	defer server.Close()
+
+	slog.Info("Waiting for 2FA code", "url", "http://"+listener.Addr().String()+OTPPath)
+
+	select {
+	case <-ctx.Done():
+		return "", fmt.Errorf("no OTP received: %w", ctx.Err())
+	case code := <-s.codes:
+		return code, nil
+	}
+}
+
+// ServeHTTP accepts the OTP as the code parameter of the query or a form.
+func (s *HTTPOTPSource) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != OTPPath {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	otp, err := parseOTP(r.FormValue("code"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	select {
+	case s.codes <- otp:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "OTP already received", http.StatusConflict)
+	}
+}
+
+// readLine reads a single line and parses the OTP from it.
+func readLine(ctx context.Context, reader *bufio.Reader) (string, error) {
+	type result struct {
+		line string
+		err  error
+	}
+
+	lines := make(chan result, 1)
+
+	go func() {
+		line, err := reader.ReadString('\n')
+		lines <- result{line: line, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", fmt.Errorf("no OTP received: %w", ctx.Err())
+	case res := <-lines:
+		if res.err != nil && (!errors.Is(res.err, io.EOF) || res.line == "") {
+			return "", fmt.Errorf("could not read OTP: %w", res.err)
+		}
+
+		return parseOTP(res.line)
+	}
+}
+
+// parseOTP validates the OTP, accepting both <code> and OTP=<code>.
+func parseOTP(input string) (string, error) {
+	matches := otpPattern.FindStringSubmatch(strings.TrimSpace(input))
+	if matches == nil {
+		return "", ErrInvalidOTP
+	}
+
+	return matches[1], nil
+}
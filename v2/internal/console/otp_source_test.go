@@ -0,0 +1,160 @@
+package console_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/console"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdinOTPSource_ReadOTP(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		expected    string
+		expectError error
+	}{
+		{
+			name:     "Plain code",
+			input:    "123456\n",
+			expected: "123456",
+		},
+		{
+			name:     "Key value format",
+			input:    "OTP=1234\n",
+			expected: "1234",
+		},
+		{
+			name:     "Without trailing newline",
+			input:    "  4321",
+			expected: "4321",
+		},
+		{
+			name:        "Not a code",
+			input:       "abc\n",
+			expectError: console.ErrInvalidOTP,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := console.NewStdinOTPSource(strings.NewReader(testCase.input))
+
+			otp, err := source.ReadOTP(context.Background())
+			if testCase.expectError != nil {
+				assert.ErrorIs(t, err, testCase.expectError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, otp)
+		})
+	}
+}
+
+func TestPipeOTPSource_ReadOTP(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "otp")
+	source := console.NewPipeOTPSource(path)
+
+	go func() {
+		// Written once the source has created the pipe
+		for {
+			if _, err := os.Stat(path); err == nil {
+				break
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		pipe, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return
+		}
+
+		_, _ = pipe.WriteString("654321\n")
+		_ = pipe.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	otp, err := source.ReadOTP(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "654321", otp)
+	assert.NoFileExists(t, path)
+}
+
+func TestHTTPOTPSource(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it accepts the code", func(t *testing.T) {
+		t.Parallel()
+
+		source := console.NewHTTPOTPSource("127.0.0.1:0")
+
+		recorder := httptest.NewRecorder()
+		source.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, console.OTPPath+"?code=1234", nil))
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+
+		otp, err := source.ReadOTP(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "1234", otp)
+	})
+
+	t.Run("it rejects invalid codes", func(t *testing.T) {
+		t.Parallel()
+
+		source := console.NewHTTPOTPSource("127.0.0.1:0")
+
+		recorder := httptest.NewRecorder()
+		source.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, console.OTPPath+"?code=x", nil))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := source.ReadOTP(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestNewOTPSource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		spec     string
+		expected any
+	}{
+		{spec: "stdin", expected: &console.StdinOTPSource{}},
+		{spec: "pipe:/tmp/otp", expected: &console.PipeOTPSource{}},
+		{spec: "http:127.0.0.1:8099", expected: &console.HTTPOTPSource{}},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.spec, func(t *testing.T) {
+			t.Parallel()
+
+			source, err := console.NewOTPSource(testCase.spec, strings.NewReader(""))
+			require.NoError(t, err)
+			assert.IsType(t, testCase.expected, source)
+		})
+	}
+
+	_, err := console.NewOTPSource("email", strings.NewReader(""))
+	assert.ErrorIs(t, err, console.ErrUnknownOTPSource)
+}
@@ -0,0 +1,92 @@
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+const (
+	// PhoneNumberKey is the environment variable or file key holding the phone number.
+	PhoneNumberKey = "TR_PHONE_NUMBER"
+	// PINKey is the environment variable or file key holding the PIN.
+	PINKey = "TR_PIN"
+
+	// DefaultOTPTimeout is how long the OTP is waited for unless configured otherwise.
+	DefaultOTPTimeout = 5 * time.Minute
+)
+
+var ErrMissingCredential = errors.New("credential not provided")
+
+// ScriptedInputHandler provides the authentication input without prompting, for scheduled runs.
+// Phone number and PIN are read from the environment or a file, the OTP from an OTPSource.
+type ScriptedInputHandler struct {
+	credentials map[string]string
+	otp         OTPSource
+	timeout     time.Duration
+}
+
+// NewScriptedInputHandler creates a new ScriptedInputHandler. If credentialsFile is empty, phone number
+// and PIN are read from the environment, otherwise from the file in .env format.
+func NewScriptedInputHandler(credentialsFile string, otp OTPSource, timeout time.Duration) (*ScriptedInputHandler, error) {
+	credentials := map[string]string{
+		PhoneNumberKey: os.Getenv(PhoneNumberKey),
+		PINKey:         os.Getenv(PINKey),
+	}
+
+	if credentialsFile != "" {
+		var err error
+
+		credentials, err = godotenv.Read(credentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read '%s' file: %w", credentialsFile, err)
+		}
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultOTPTimeout
+	}
+
+	return &ScriptedInputHandler{
+		credentials: credentials,
+		otp:         otp,
+		timeout:     timeout,
+	}, nil
+}
+
+// GetPhoneNumber returns the configured phone number.
+func (h *ScriptedInputHandler) GetPhoneNumber() (string, error) {
+	return h.credential(PhoneNumberKey)
+}
+
+// GetPIN returns the configured PIN.
+func (h *ScriptedInputHandler) GetPIN() (string, error) {
+	return h.credential(PINKey)
+}
+
+// GetOTP waits for the OTP from the source until the timeout passes.
+func (h *ScriptedInputHandler) GetOTP() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+
+	defer cancel()
+
+	otp, err := h.otp.ReadOTP(ctx)
+	if err != nil {
+		return "", fmt.Errorf("could not read OTP: %w", err)
+	}
+
+	return otp, nil
+}
+
+func (h *ScriptedInputHandler) credential(key string) (string, error) {
+	value := h.credentials[key]
+	if value == "" {
+		return "", fmt.Errorf("%w: %s", ErrMissingCredential, key)
+	}
+
+	return value, nil
+}
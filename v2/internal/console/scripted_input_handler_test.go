@@ -0,0 +1,82 @@
+package console_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/console"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScriptedInputHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it reads the credentials file", func(t *testing.T) {
+		t.Parallel()
+
+		credentialsFile := filepath.Join(t.TempDir(), "credentials.env")
+
+		err := os.WriteFile(credentialsFile, []byte("TR_PHONE_NUMBER=+491234567890\nTR_PIN=1234\n"), 0o600)
+		require.NoError(t, err)
+
+		handler, err := console.NewScriptedInputHandler(
+			credentialsFile,
+			console.NewStdinOTPSource(strings.NewReader("OTP=5678\n")),
+			0,
+		)
+		require.NoError(t, err)
+
+		phoneNumber, err := handler.GetPhoneNumber()
+		require.NoError(t, err)
+		assert.Equal(t, "+491234567890", phoneNumber)
+
+		pin, err := handler.GetPIN()
+		require.NoError(t, err)
+		assert.Equal(t, "1234", pin)
+
+		otp, err := handler.GetOTP()
+		require.NoError(t, err)
+		assert.Equal(t, "5678", otp)
+	})
+
+	t.Run("it reports missing credentials", func(t *testing.T) {
+		t.Parallel()
+
+		credentialsFile := filepath.Join(t.TempDir(), "empty.env")
+
+		err := os.WriteFile(credentialsFile, []byte("TR_PHONE_NUMBER=+491234567890\n"), 0o600)
+		require.NoError(t, err)
+
+		handler, err := console.NewScriptedInputHandler(credentialsFile, console.NewStdinOTPSource(strings.NewReader("")), 0)
+		require.NoError(t, err)
+
+		_, err = handler.GetPIN()
+		assert.ErrorIs(t, err, console.ErrMissingCredential)
+	})
+
+	t.Run("it fails on a missing file", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := console.NewScriptedInputHandler(filepath.Join(t.TempDir(), "missing.env"), nil, 0)
+		assert.Error(t, err)
+	})
+}
+
+func TestScriptedInputHandler_Environment(t *testing.T) {
+	t.Setenv(console.PhoneNumberKey, "+49111")
+	t.Setenv(console.PINKey, "9999")
+
+	handler, err := console.NewScriptedInputHandler("", nil, 0)
+	require.NoError(t, err)
+
+	phoneNumber, err := handler.GetPhoneNumber()
+	require.NoError(t, err)
+	assert.Equal(t, "+49111", phoneNumber)
+
+	pin, err := handler.GetPIN()
+	require.NoError(t, err)
+	assert.Equal(t, "9999", pin)
+}
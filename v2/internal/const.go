@@ -8,6 +8,9 @@ const (
 
 	AuthTokenFilename = "./.auth"
 
+	// EncryptedAuthTokenFilename is the file the credentials are stored in when encrypted with a passphrase.
+	EncryptedAuthTokenFilename = "./.auth.age"
+
 	// ResponseActionTypeTimelineDetail represents the value the app will look for in order to determine
 	// if any details can be fetched.
 	ResponseActionTypeTimelineDetail = "timelineDetail"
@@ -20,9 +23,21 @@ const (
 
 	ResponseBaseDir = "./debug/responses"
 
+	// ProfilesBaseDir base directory under which the files of named profiles are kept.
+	ProfilesBaseDir = "./profiles"
+
 	// CSVFilename filename under which a CSV file with transaction entries has to be saved.
 	CSVFilename = "./transactions.csv"
 
+	// SyncStateFilename filename under which the transactions downloaded so far are saved for incremental runs.
+	SyncStateFilename = "./.sync.json"
+
+	// DeadLetterBaseDir base directory under which events that failed processing are kept for a retry.
+	DeadLetterBaseDir = "./failed"
+
+	// CheckpointFilename filename under which the state of the last run is saved to resume interrupted runs.
+	CheckpointFilename = "./.checkpoint.json"
+
 	// TransactionDocumentsBaseDir base directory under which downloaded transaction documents are saved.
 	TransactionDocumentsBaseDir = "./documents/transactions"
 
@@ -0,0 +1,220 @@
+// Package deadletter keeps the events that failed processing on disk, so they can be replayed once the
+// cause is fixed.
+package deadletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/writer"
+)
+
+var ErrInvalidEvent = errors.New("invalid dead letter event")
+
+// Entry is a failed event as stored on disk.
+type Entry struct {
+	Topic      string            `json:"topic"`
+	ID         string            `json:"id"`
+	Errors     []string          `json:"errors"`               // Error chain, outermost first
+	Attributes map[string]string `json:"attributes,omitempty"` // Context the event needs to be replayed
+	Payload    json.RawMessage   `json:"payload,omitempty"`    // Raw payload when it is valid JSON
+	Raw        []byte            `json:"raw,omitempty"`        // Raw payload otherwise
+	FailedAt   time.Time         `json:"failedAt"`
+
+	path string
+}
+
+// Event returns the event to replay.
+func (e Entry) Event() bus.Event {
+	if e.Payload != nil {
+		return bus.NewEvent(e.Topic, e.ID, []byte(e.Payload))
+	}
+
+	return bus.NewEvent(e.Topic, e.ID, e.Raw)
+}
+
+// Store persists dead letters as one JSON file per event under <dir>/<topic>/<id>.json.
+type Store struct {
+	dir     string
+	written map[string]struct{} // IDs of the transactions whose rows were written by the run
+	mu      sync.Mutex
+}
+
+// NewStore creates a new Store keeping the files under dir.
+func NewStore(dir string) *Store {
+	return &Store{
+		dir:     dir,
+		written: make(map[string]struct{}),
+	}
+}
+
+// Handle persists the failed event of a bus.TopicDeadLetter event, a later failure of the same event
+// replaces the earlier one.
+func (s *Store) Handle(event bus.Event) error {
+	failed, ok := event.Data.(bus.FailedEvent)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrInvalidEvent, event.Data)
+	}
+
+	entry := Entry{
+		Topic:      failed.Event.Topic,
+		ID:         failed.Event.ID,
+		Errors:     errorChain(failed.Err),
+		Attributes: failed.Attributes,
+		FailedAt:   time.Now().UTC(),
+	}
+
+	payload, _ := failed.Event.Data.([]byte)
+	if json.Valid(payload) {
+		entry.Payload = payload
+	} else {
+		entry.Raw = payload
+	}
+
+	// Not indented, so the payload is kept byte for byte
+	contents, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	filePath := s.path(entry.Topic, entry.ID)
+
+	err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("failed to create directories: %w", err)
+	}
+
+	err = writer.WriteFileAtomic(filePath, contents)
+	if err != nil {
+		return fmt.Errorf("failed to write dead letter '%s': %w", filePath, err)
+	}
+
+	return nil
+}
+
+// HandleWritten records the transaction of a bus.TopicRowWritten event, its dead letters are removed by
+// RemoveWritten.
+func (s *Store) HandleWritten(event bus.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.written[event.ID] = struct{}{}
+}
+
+// RemoveWritten deletes the dead letters of the transactions whose rows were written, it has to be called
+// once the rows are flushed. Events failing again are kept.
+func (s *Store) RemoveWritten() error {
+	entries, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range entries {
+		if _, found := s.written[entry.ID]; !found {
+			continue
+		}
+
+		err = s.Remove(entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// List returns the stored dead letters, ordered by topic and ID.
+func (s *Store) List() ([]Entry, error) {
+	var entries []Entry
+
+	err := filepath.WalkDir(s.dir, func(path string, dirEntry fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && path == s.dir {
+			return fs.SkipAll
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if dirEntry.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read '%s' file: %w", path, err)
+		}
+
+		var entry Entry
+
+		err = json.Unmarshal(contents, &entry)
+		if err != nil {
+			return fmt.Errorf("failed to parse '%s' file: %w", path, err)
+		}
+
+		entry.path = path
+		entries = append(entries, entry)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Remove deletes the stored dead letter.
+func (s *Store) Remove(entry Entry) error {
+	path := entry.path
+	if path == "" {
+		path = s.path(entry.Topic, entry.ID)
+	}
+
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove dead letter '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+func (s *Store) path(topic, id string) string {
+	// IDs are UUIDs or ISINs, anything else is kept from escaping the directory
+	safeID := strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(id)
+
+	return filepath.Join(s.dir, topic, safeID+".json")
+}
+
+// errorChain lists the messages of the error and the errors it wraps, outermost first.
+func errorChain(err error) []string {
+	var chain []string
+
+	for err != nil {
+		chain = append(chain, err.Error())
+
+		switch wrapped := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, joined := range wrapped.Unwrap() {
+				chain = append(chain, errorChain(joined)...)
+			}
+
+			return chain
+		default:
+			err = errors.Unwrap(err)
+		}
+	}
+
+	return chain
+}
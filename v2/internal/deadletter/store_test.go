@@ -0,0 +1,127 @@
+package deadletter_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/deadletter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errMissingField = errors.New("missing field")
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		payload    []byte
+		err        error
+		attributes map[string]string
+		errors     []string
+	}{
+		{
+			name:       "JSON payload with wrapped error",
+			payload:    []byte(`{"id":"a"}`),
+			err:        fmt.Errorf("failed to map: %w", errMissingField),
+			attributes: map[string]string{"eventType": "card_successful_transaction"},
+			errors:     []string{"failed to map: missing field", "missing field"},
+		},
+		{
+			name:    "Invalid payload with joined errors",
+			payload: []byte(`{"id":`),
+			err:     errors.Join(errMissingField, errors.New("unexpected end")),
+			errors:  []string{"missing field\nunexpected end", "missing field", "unexpected end"},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := deadletter.NewStore(filepath.Join(t.TempDir(), "failed"))
+
+			entries, err := store.List()
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+
+			event := bus.NewEvent(bus.TopicTimelineDetailsV2Received, "a", testCase.payload)
+			failed := bus.FailedEvent{Event: event, Err: testCase.err, Attributes: testCase.attributes}
+
+			err = store.Handle(bus.NewEvent(bus.TopicDeadLetter, event.ID, failed))
+			require.NoError(t, err)
+
+			entries, err = store.List()
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+
+			entry := entries[0]
+			assert.Equal(t, testCase.errors, entry.Errors)
+			assert.Equal(t, testCase.attributes, entry.Attributes)
+			assert.Equal(t, event, entry.Event())
+
+			err = store.Remove(entry)
+			require.NoError(t, err)
+
+			entries, err = store.List()
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestStore_Handle_InvalidEvent(t *testing.T) {
+	t.Parallel()
+
+	store := deadletter.NewStore(t.TempDir())
+
+	err := store.Handle(bus.NewEvent(bus.TopicDeadLetter, "a", []byte("{}")))
+	require.ErrorIs(t, err, deadletter.ErrInvalidEvent)
+}
+
+func TestStore_PathTraversal(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := deadletter.NewStore(filepath.Join(dir, "failed"))
+	event := bus.NewEvent(bus.TopicTimelineDetailsV2Received, "../../escaped", []byte("{}"))
+
+	err := store.Handle(bus.NewEvent(bus.TopicDeadLetter, event.ID, bus.FailedEvent{Event: event, Err: errMissingField}))
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(dir, "escaped.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	entries, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, event.ID, entries[0].ID)
+}
+
+func TestStore_RemoveWritten(t *testing.T) {
+	t.Parallel()
+
+	store := deadletter.NewStore(filepath.Join(t.TempDir(), "failed"))
+
+	for _, id := range []string{"retried", "failing"} {
+		event := bus.NewEvent(bus.TopicTimelineDetailsV2Received, id, []byte("{}"))
+
+		err := store.Handle(bus.NewEvent(bus.TopicDeadLetter, id, bus.FailedEvent{Event: event, Err: errMissingField}))
+		require.NoError(t, err)
+	}
+
+	store.HandleWritten(bus.NewEvent(bus.TopicRowWritten, "retried", nil))
+
+	err := store.RemoveWritten()
+	require.NoError(t, err)
+
+	entries, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "failing", entries[0].ID)
+}
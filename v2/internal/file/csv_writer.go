@@ -3,68 +3,173 @@
 package file
 
 import (
+	"bytes"
+	"encoding/csv"
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"sync"
 
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/transaction"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/writer"
 	"github.com/gocarina/gocsv"
 )
 
-const permFile = 0o600
+// csvIDColumn is the header of the column identifying the transaction of a row.
+const csvIDColumn = "ID"
 
 type CSVWriterInterface interface {
 	Write(filepath string, entry transaction.Model) error
+	Flush() error
 }
 
+// CSVWriter buffers the entries and writes them on Flush, so the CSV files only ever contain complete runs
+// of rows, even when a run is interrupted. Interrupted runs are flushed once their in-flight work drained,
+// a run that is killed or crashes loses the rows it buffered. The checkpoint, sync state and dead letters
+// are only saved after the flush as well, so the next run downloads those transactions again.
 type CSVWriter struct {
-	mu *sync.Mutex
+	pending map[string][]transaction.Model // Entries waiting to be written keyed by the file
+	mu      *sync.Mutex
 }
 
 func NewCSVWriter() *CSVWriter {
 	return &CSVWriter{
-		mu: &sync.Mutex{},
+		pending: make(map[string][]transaction.Model),
+		mu:      &sync.Mutex{},
 	}
 }
 
+// Write buffers the entry until the next Flush.
 func (w *CSVWriter) Write(filepath string, entry transaction.Model) error {
 	w.mu.Lock()
 
 	defer w.mu.Unlock()
 
-	entries := []transaction.Model{entry}
-	newFile := false
+	w.pending[filepath] = append(w.pending[filepath], entry)
 
-	_, err := os.Stat(filepath)
-	if os.IsNotExist(err) {
-		newFile = true
+	return nil
+}
+
+// Flush writes the buffered entries to their files, each file is replaced at once. Entries already in the
+// file, e.g. transactions whose status changed since, replace their rows instead of being added again.
+func (w *CSVWriter) Flush() error {
+	w.mu.Lock()
+
+	defer w.mu.Unlock()
+
+	for filepath, entries := range w.pending {
+		err := upsertEntries(filepath, entries)
+		if err != nil {
+			return err
+		}
+
+		delete(w.pending, filepath)
 	}
 
-	file, err := os.OpenFile(filepath, os.O_RDWR|os.O_CREATE|os.O_APPEND, permFile)
+	return nil
+}
+
+// upsertEntries writes the existing rows with the ones of the entries replaced, followed by the new entries.
+// The header is taken from the entries, rows written with another header are moved to the columns of the
+// same name.
+func upsertEntries(filepath string, entries []transaction.Model) error {
+	contents, err := os.ReadFile(filepath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read csv file: %w", err)
+	}
+
+	rows, err := csv.NewReader(bytes.NewReader(contents)).ReadAll()
+	if err != nil {
+		return fmt.Errorf("could not read csv file: %w", err)
+	}
+
+	marshalled, err := gocsv.MarshalBytes(&entries)
+	if err != nil {
+		return fmt.Errorf("could not write csv file: %w", err)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(marshalled)).ReadAll()
 	if err != nil {
-		return fmt.Errorf("could not open csv file: %w", err)
+		return fmt.Errorf("could not write csv file: %w", err)
 	}
 
-	defer func() {
-		if err := file.Close(); err != nil {
-			slog.Error("csv writer could not close file", "err", err)
+	header := records[0]
+
+	if len(rows) == 0 {
+		rows = [][]string{header}
+	}
+
+	// Files written by older versions lack the columns added since
+	if !slices.Equal(rows[0], header) {
+		slog.Info("Moving csv rows to the current columns", "file", filepath, "columns", len(header))
+
+		rows = remapRows(rows, header)
+	}
+
+	idColumn := slices.Index(header, csvIDColumn)
+	positions := make(map[string]int, len(rows))
+
+	// Rows of files that had no ID column are never replaced
+	for position, row := range rows[1:] {
+		if row[idColumn] != "" {
+			positions[row[idColumn]] = position + 1
 		}
-	}()
+	}
 
-	if newFile {
-		err = gocsv.MarshalFile(&entries, file)
-		if err != nil {
-			return fmt.Errorf("could not write csv file: %w", err)
+	for _, record := range records[1:] {
+		id := record[idColumn]
+
+		position, found := positions[id]
+		if found {
+			rows[position] = record
+
+			continue
 		}
 
-		return nil
+		positions[id] = len(rows)
+		rows = append(rows, record)
+	}
+
+	buffer := &bytes.Buffer{}
+	csvWriter := csv.NewWriter(buffer)
+
+	err = csvWriter.WriteAll(rows)
+	if err != nil {
+		return fmt.Errorf("could not write csv file: %w", err)
 	}
 
-	err = gocsv.MarshalWithoutHeaders(&entries, file)
+	err = writer.WriteFileAtomic(filepath, buffer.Bytes())
 	if err != nil {
 		return fmt.Errorf("could not write csv file: %w", err)
 	}
 
 	return nil
 }
+
+// remapRows moves the values of the rows to the columns of the same name in the header, columns the header
+// does not have anymore are dropped and the ones it added are left empty.
+func remapRows(rows [][]string, header []string) [][]string {
+	columns := make(map[string]int, len(rows[0]))
+	for column, name := range rows[0] {
+		columns[name] = column
+	}
+
+	remapped := make([][]string, 0, len(rows))
+	remapped = append(remapped, header)
+
+	for _, row := range rows[1:] {
+		record := make([]string, len(header))
+
+		for column, name := range header {
+			previous, found := columns[name]
+			if found && previous < len(row) {
+				record[column] = row[previous]
+			}
+		}
+
+		remapped = append(remapped, record)
+	}
+
+	return remapped
+}
@@ -40,6 +40,20 @@ func (m *MockCSVWriterInterface) EXPECT() *MockCSVWriterInterfaceMockRecorder {
 	return m.recorder
 }
 
+// Flush mocks base method.
+func (m *MockCSVWriterInterface) Flush() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockCSVWriterInterfaceMockRecorder) Flush() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockCSVWriterInterface)(nil).Flush))
+}
+
 // Write mocks base method.
 func (m *MockCSVWriterInterface) Write(filepath string, entry transaction.Model) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,117 @@
+package file_test
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/file"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVWriter_Flush(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "transactions.csv")
+	writer := file.NewCSVWriter()
+
+	require.NoError(t, writer.Write(filePath, transaction.Model{ID: "first"}))
+	assert.NoFileExists(t, filePath, "entries are only written on flush")
+
+	require.NoError(t, writer.Flush())
+
+	require.NoError(t, writer.Write(filePath, transaction.Model{ID: "second"}))
+	require.NoError(t, writer.Flush())
+
+	// Nothing is pending anymore
+	require.NoError(t, writer.Flush())
+
+	contents, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+	require.Len(t, lines, 3, "header and two entries expected")
+	assert.Contains(t, lines[1], "first")
+	assert.Contains(t, lines[2], "second")
+}
+
+func TestCSVWriter_Flush_ReplacesExistingRows(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "transactions.csv")
+	writer := file.NewCSVWriter()
+
+	require.NoError(t, writer.Write(filePath, transaction.Model{ID: "first", Status: "PENDING"}))
+	require.NoError(t, writer.Write(filePath, transaction.Model{ID: "second", Status: "EXECUTED"}))
+	require.NoError(t, writer.Flush())
+
+	require.NoError(t, writer.Write(filePath, transaction.Model{ID: "first", Status: "EXECUTED"}))
+	require.NoError(t, writer.Write(filePath, transaction.Model{ID: "third", Status: "EXECUTED"}))
+	require.NoError(t, writer.Flush())
+
+	contents, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+	require.Len(t, lines, 4, "header and three entries expected")
+	assert.True(t, strings.HasPrefix(lines[1], "first,EXECUTED"), "changed entry replaces its row")
+	assert.True(t, strings.HasPrefix(lines[2], "second,EXECUTED"))
+	assert.True(t, strings.HasPrefix(lines[3], "third,EXECUTED"))
+}
+
+func TestCSVWriter_Flush_MigratesHeader(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "transactions.csv")
+
+	// Header written before the derivative, dividend, card and transfer columns were added
+	baseline := "ID,Status,Timestamp,Type,AssetType,AssetName,ISIN,Shares,SharePrice,Yield,Gain,Fee,Debit,Credit,TaxAmount,Documents\n" +
+		"old,EXECUTED,2024-01-02 10:00:00,Buy order,STOCK,Apple,US0378331005,2,150,,,1,301,,,doc.pdf\n" +
+		"pending,PENDING,2024-01-03 10:00:00,Buy order,STOCK,Tesla,US88160R1014,1,200,,,1,201,,,\n"
+
+	require.NoError(t, os.WriteFile(filePath, []byte(baseline), 0o600))
+
+	writer := file.NewCSVWriter()
+
+	require.NoError(t, writer.Write(filePath, transaction.Model{ID: "pending", Status: "EXECUTED"}))
+	require.NoError(t, writer.Write(filePath, transaction.Model{ID: "new", Status: "EXECUTED", Merchant: "Aldi"}))
+	require.NoError(t, writer.Flush())
+
+	contents, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(strings.NewReader(string(contents))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 4, "header and three entries expected")
+
+	header := rows[0]
+	for _, column := range []string{"ProductType", "Underlying", "Gross", "Merchant", "IBAN", "SharePriceCurrency"} {
+		assert.Contains(t, header, column)
+	}
+
+	value := func(row []string, column string) string {
+		for i, name := range header {
+			if name == column {
+				return row[i]
+			}
+		}
+
+		return ""
+	}
+
+	assert.Equal(t, "old", value(rows[1], "ID"))
+	assert.Equal(t, "US0378331005", value(rows[1], "ISIN"))
+	assert.Equal(t, "301", value(rows[1], "Debit"))
+	assert.Equal(t, "doc.pdf", value(rows[1], "Documents"))
+	assert.Empty(t, value(rows[1], "Merchant"))
+
+	assert.Equal(t, "pending", value(rows[2], "ID"))
+	assert.Equal(t, "EXECUTED", value(rows[2], "Status"), "changed entry replaces its row")
+
+	assert.Equal(t, "new", value(rows[3], "ID"))
+	assert.Equal(t, "Aldi", value(rows[3], "Merchant"))
+}
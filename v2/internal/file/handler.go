@@ -5,6 +5,7 @@ import (
 	"log/slog"
 
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/checkpoint"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/transaction"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/writer"
 )
@@ -34,14 +35,23 @@ func (h *RawResponseHandler) Handle(event bus.Event) {
 }
 
 type CSVHander struct {
-	filepath string
-	writer   CSVWriterInterface
+	filepath   string
+	writer     CSVWriterInterface
+	eventBus   bus.EventBusInterface
+	checkpoint *checkpoint.Store
 }
 
-func NewCSVHandler(filepath string, writer CSVWriterInterface) *CSVHander {
+func NewCSVHandler(
+	filepath string,
+	writer CSVWriterInterface,
+	eventBus bus.EventBusInterface,
+	checkpoint *checkpoint.Store,
+) *CSVHander {
 	return &CSVHander{
-		filepath: filepath,
-		writer:   writer,
+		filepath:   filepath,
+		writer:     writer,
+		eventBus:   eventBus,
+		checkpoint: checkpoint,
 	}
 }
 
@@ -49,10 +59,22 @@ func (h *CSVHander) Handle(event bus.Event) {
 	model, ok := event.Data.(transaction.Model)
 	if !ok {
 		slog.Error("invalid model received", "id", event.ID)
+		h.eventBus.Tracker().Count(bus.WorkCSVRow, bus.OutcomeFailed)
+
+		return
 	}
 
 	err := h.writer.Write(h.filepath, model)
 	if err != nil {
 		slog.Error("failed to write entry to csv", "id", event.ID, "filepath", h.filepath, "err", err)
+		h.eventBus.Tracker().Count(bus.WorkCSVRow, bus.OutcomeFailed)
+
+		return
 	}
+
+	h.checkpoint.MarkProcessed(model.ID)
+	h.eventBus.Tracker().Count(bus.WorkCSVRow, bus.OutcomeWritten)
+
+	// Written rows are only on disk after the flush, so their state is persisted after it
+	h.eventBus.Publish(bus.NewEvent(bus.TopicRowWritten, model.ID, nil))
 }
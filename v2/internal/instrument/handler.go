@@ -2,7 +2,9 @@ package instrument
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/message"
@@ -11,13 +13,25 @@ import (
 	gocache "github.com/patrickmn/go-cache"
 )
 
+// failureTTL is how long the error of a failed instrument subscription is kept for the transactions waiting for it.
+const failureTTL = 10 * time.Second
+
 type Handler struct {
+	ctx       context.Context // Run the instruments are subscribed for, waits for the rate limiter stop once it is done
+	eventBus  bus.EventBusInterface
 	msgClient message.ClientInterface
 	cache     *gocache.Cache
 }
 
-func NewHandler(msgClient message.ClientInterface, cache *gocache.Cache) *Handler {
+func NewHandler(
+	ctx context.Context,
+	eventBus bus.EventBusInterface,
+	msgClient message.ClientInterface,
+	cache *gocache.Cache,
+) *Handler {
 	return &Handler{
+		ctx:       ctx,
+		eventBus:  eventBus,
 		msgClient: msgClient,
 		cache:     cache,
 	}
@@ -39,12 +53,14 @@ func (h *Handler) HandleFetch(event bus.Event) {
 		return
 	}
 
-	err := h.msgClient.SubsribeToInstrument(context.Background(), isin)
+	err := h.msgClient.SubsribeToInstrument(h.ctx, isin)
 	if err != nil {
 		slog.Error("failed to subscribe to instrument", "isin", isin, "error", err)
 	}
 }
 
+// HandleReceived caches the received instrument, replacing the error of a subscription that failed before.
+// Transactions waiting for it are notified by a bus.TopicInstrumentCached event.
 func (h *Handler) HandleReceived(event bus.Event) {
 	isin := event.ID
 
@@ -52,8 +68,26 @@ func (h *Handler) HandleReceived(event bus.Event) {
 
 	err := instr.UnmarshalJSON(event.Data.([]byte))
 	if err != nil {
-		slog.Error("failed to unmarshal instrument", "isin", isin)
+		slog.Error("failed to unmarshal instrument", "isin", isin, "error", err)
+		h.cache.Set(isin, fmt.Errorf("failed to unmarshal instrument: %w", err), failureTTL)
+		h.eventBus.Publish(bus.NewEvent(bus.TopicInstrumentCached, isin, nil))
+
+		return
+	}
+
+	h.cache.Set(isin, instr, gocache.NoExpiration)
+	h.eventBus.Publish(bus.NewEvent(bus.TopicInstrumentCached, isin, nil))
+}
+
+// HandleFailed caches the error of a failed instrument subscription, so transactions waiting for the
+// instrument fail instead of waiting for it. The error expires after failureTTL, so transactions of the
+// instrument received later subscribe to it again.
+func (h *Handler) HandleFailed(event bus.Event) {
+	failure, ok := event.Data.(message.Failure)
+	if !ok || failure.Type != traderepublic.WsSubRequestJsonTypeInstrument {
+		return
 	}
 
-	_ = h.cache.Add(isin, instr, gocache.NoExpiration)
+	h.cache.Set(event.ID, failure.Err, failureTTL)
+	h.eventBus.Publish(bus.NewEvent(bus.TopicInstrumentCached, event.ID, nil))
 }
@@ -0,0 +1,55 @@
+package instrument_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/instrument"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/message"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const isin = "US6701002056"
+
+func TestHandler_HandleReceived_ReplacesFailure(t *testing.T) {
+	t.Parallel()
+
+	cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
+	handler := instrument.NewHandler(context.Background(), bus.New(), nil, cache)
+
+	handler.HandleFailed(bus.NewEvent(bus.TopicSubscriptionFailed, isin, message.Failure{
+		Type: traderepublic.WsSubRequestJsonTypeInstrument,
+		Err:  errors.New("subscription timed out"),
+	}))
+
+	entry, found := cache.Get(isin)
+	require.True(t, found)
+
+	_, failed := entry.(error)
+	assert.True(t, failed)
+
+	shortName := "NVIDIA"
+	data, err := json.Marshal(traderepublic.InstrumentJson{
+		Isin:      isin,
+		Name:      "NVIDIA Corp.",
+		ShortName: &shortName,
+		TypeId:    traderepublic.InstrumentJsonTypeIdStock,
+	})
+	require.NoError(t, err)
+
+	handler.HandleReceived(bus.NewEvent(bus.TopicInstrumentReceived, isin, data))
+
+	entry, found = cache.Get(isin)
+	require.True(t, found)
+
+	instr, ok := entry.(traderepublic.InstrumentJson)
+	require.True(t, ok, "the failure should be replaced by the instrument")
+	assert.Equal(t, isin, instr.Isin)
+	assert.Equal(t, &shortName, instr.ShortName)
+}
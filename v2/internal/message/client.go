@@ -17,20 +17,52 @@ type ClientInterface interface {
 	SubsribeToInstrument(ctx context.Context, isin string) error
 }
 
+// Failure is the data of a TopicSubscriptionFailed event, the ID of the event is the subscribed item.
+type Failure struct {
+	Type traderepublic.WsSubRequestJsonType
+	Err  error
+}
+
+// subscriptionTypes maps the topics the data is published to to the types of the subscriptions.
+var subscriptionTypes = map[string]traderepublic.WsSubRequestJsonType{
+	bus.TopicTimelineTransactionsReceived: traderepublic.WsSubRequestJsonTypeTimelineTransactions,
+	bus.TopicTimelineDetailsV2Received:    traderepublic.WsSubRequestJsonTypeTimelineDetailV2,
+	bus.TopicInstrumentReceived:           traderepublic.WsSubRequestJsonTypeInstrument,
+}
+
+// KnownTransactions reports whether a timeline transaction was downloaded before with the same status.
+type KnownTransactions interface {
+	Known(id, status string) bool
+}
+
 type Client struct {
-	eventBus           *bus.EventBus
+	eventBus           bus.EventBusInterface
 	credentialsService auth.CredentialsServiceInterface
 	wsClient           traderepublic.WSClientInterface
+	limiter            *Limiter
+	known              KnownTransactions
 }
 
-func NewClient(eventBus *bus.EventBus, credentialsService auth.CredentialsServiceInterface, wsClient traderepublic.WSClientInterface) *Client {
+func NewClient(
+	eventBus bus.EventBusInterface,
+	credentialsService auth.CredentialsServiceInterface,
+	wsClient traderepublic.WSClientInterface,
+	limiter *Limiter,
+) *Client {
 	return &Client{
 		eventBus:           eventBus,
 		credentialsService: credentialsService,
 		wsClient:           wsClient,
+		limiter:            limiter,
 	}
 }
 
+// SetKnownTransactions makes paging stop at the first page whose transactions are all known, older
+// pages are expected to be known as well. Without it every page is fetched.
+func (c *Client) SetKnownTransactions(known KnownTransactions) {
+	c.known = known
+}
+
 // SubscribeToTimelineTransactions subscribes to timeline transactions data.
 func (c *Client) SubscribeToTimelineTransactions(ctx context.Context) error {
 	ch, err := c.SubscribeToTimelineTransactionsWithCursor(ctx, "")
@@ -38,14 +70,12 @@ func (c *Client) SubscribeToTimelineTransactions(ctx context.Context) error {
 		return err
 	}
 
-	data := <-ch
 	counter := int64(1)
 
-	c.eventBus.Publish(bus.NewEvent(
-		bus.TopicTimelineTransactionsReceived,
-		strconv.FormatInt(counter, 10),
-		data,
-	))
+	data, err := c.receive(ch, bus.TopicTimelineTransactionsReceived, strconv.FormatInt(counter, 10))
+	if err != nil {
+		return err
+	}
 
 	var response traderepublic.TimelineTransactionsJson
 
@@ -56,8 +86,19 @@ func (c *Client) SubscribeToTimelineTransactions(ctx context.Context) error {
 
 	var mu sync.Mutex
 
+	// Following pages are pending work until the last one is received
+	c.eventBus.Tracker().Add()
+
 	go func() {
+		defer c.eventBus.Tracker().Done()
+
 		for response.Cursors.After != nil {
+			if c.pageKnown(response) {
+				slog.Info("Reached transactions downloaded before, older pages are skipped", "pages", counter)
+
+				return
+			}
+
 			ch, err = c.SubscribeToTimelineTransactionsWithCursor(ctx, *response.Cursors.After)
 			if err != nil {
 				slog.Error("error subscribing to timeline transactions", "error", err)
@@ -65,19 +106,16 @@ func (c *Client) SubscribeToTimelineTransactions(ctx context.Context) error {
 				return
 			}
 
-			data = <-ch
-
 			mu.Lock()
 
 			counter++
 
 			mu.Unlock()
 
-			c.eventBus.Publish(bus.NewEvent(
-				bus.TopicTimelineTransactionsReceived,
-				strconv.FormatInt(counter, 10),
-				data,
-			))
+			data, err = c.receive(ch, bus.TopicTimelineTransactionsReceived, strconv.FormatInt(counter, 10))
+			if err != nil {
+				return
+			}
 
 			err = response.UnmarshalJSON(data)
 			if err != nil {
@@ -91,15 +129,33 @@ func (c *Client) SubscribeToTimelineTransactions(ctx context.Context) error {
 	return nil
 }
 
+// pageKnown reports whether all transactions of the page are known.
+func (c *Client) pageKnown(page traderepublic.TimelineTransactionsJson) bool {
+	if c.known == nil || len(page.Items) == 0 {
+		return false
+	}
+
+	for _, item := range page.Items {
+		if !c.known.Known(string(item.Id), string(item.Status)) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // SubscribeToTimelineTransactionsWithCursor subscribes to timeline transactions data with a cursor.
-func (c *Client) SubscribeToTimelineTransactionsWithCursor(ctx context.Context, cursor string) (<-chan []byte, error) {
+func (c *Client) SubscribeToTimelineTransactionsWithCursor(
+	ctx context.Context,
+	cursor string,
+) (<-chan traderepublic.SubscriptionResult, error) {
 	data := traderepublic.WsSubRequestJson{
 		Token: c.credentialsService.GetToken().Session(),
 		Type:  traderepublic.WsSubRequestJsonTypeTimelineTransactions,
 		After: &cursor,
 	}
 
-	return c.wsClient.Subscribe(data)
+	return c.subscribe(ctx, data)
 }
 
 // SubscribeToTimelineDetail subscribes to timeline detail data.
@@ -111,19 +167,13 @@ func (c *Client) SubscribeToTimelineDetailV2(ctx context.Context, uuid traderepu
 		Type:  traderepublic.WsSubRequestJsonTypeTimelineDetailV2,
 	}
 
-	ch, err := c.wsClient.Subscribe(data)
+	ch, err := c.subscribe(ctx, data)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	go func() {
-		data := <-ch
-
-		c.eventBus.Publish(bus.NewEvent(
-			bus.TopicTimelineDetailsV2Received,
-			itemID,
-			data,
-		))
+		_, _ = c.receive(ch, bus.TopicTimelineDetailsV2Received, itemID)
 	}()
 
 	return nil
@@ -138,20 +188,89 @@ func (c *Client) SubsribeToInstrument(ctx context.Context, isin string) error {
 		Jurisdiction: &jurisdiction,
 	}
 
+	ch, err := c.subscribe(ctx, data)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		_, _ = c.receive(ch, bus.TopicInstrumentReceived, isin)
+	}()
+
+	return nil
+}
+
+// subscribe sends the subscription once the limiter allows it, the slot is released when the data is received.
+// The subscription is pending work until its result is passed to receive.
+func (c *Client) subscribe(
+	ctx context.Context,
+	data traderepublic.WsSubRequestJson,
+) (<-chan traderepublic.SubscriptionResult, error) {
+	tracker := c.eventBus.Tracker()
+
+	tracker.Add()
+
+	err := c.limiter.Acquire(ctx)
+	if err != nil {
+		tracker.Count(string(data.Type), bus.OutcomeFailed)
+		tracker.Done()
+
+		return nil, err
+	}
+
 	ch, err := c.wsClient.Subscribe(data)
 	if err != nil {
-		return nil
+		c.limiter.Release()
+		tracker.Count(string(data.Type), bus.OutcomeFailed)
+		tracker.Done()
+
+		return nil, err
 	}
 
+	released := make(chan traderepublic.SubscriptionResult, 1)
+
 	go func() {
-		data := <-ch
+		defer close(released)
 
-		c.eventBus.Publish(bus.NewEvent(
-			bus.TopicInstrumentReceived,
-			isin,
-			data,
-		))
+		result, ok := <-ch
+
+		c.limiter.Release()
+
+		if !ok {
+			result.Err = traderepublic.ErrConnectionClosed
+		}
+
+		released <- result
 	}()
 
-	return nil
+	return released, nil
+}
+
+// receive waits for the result of a subscription and publishes the data to the topic, failures are
+// published as TopicSubscriptionFailed events.
+func (c *Client) receive(ch <-chan traderepublic.SubscriptionResult, topic, id string) ([]byte, error) {
+	tracker := c.eventBus.Tracker()
+
+	defer tracker.Done()
+
+	subType := subscriptionTypes[topic]
+	result := <-ch
+
+	if result.Err == nil {
+		c.eventBus.Publish(bus.NewEvent(topic, id, result.Data))
+		tracker.Count(string(subType), bus.OutcomeReceived)
+
+		return result.Data, nil
+	}
+
+	slog.Error("subscription failed", "type", subType, "id", id, "error", result.Err)
+
+	c.eventBus.Publish(bus.NewEvent(
+		bus.TopicSubscriptionFailed,
+		id,
+		Failure{Type: subType, Err: result.Err},
+	))
+	tracker.Count(string(subType), bus.OutcomeFailed)
+
+	return nil, result.Err
 }
@@ -0,0 +1,95 @@
+package message_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/message"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/syncstate"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/traderepublic/auth"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	executedID = "0f3c8b1e-6a2d-4c5e-9b7f-1d2e3f4a5b6c"
+	canceledID = "7a9b1c3d-5e7f-4a1b-8c3d-5e7f9a1b3c5d"
+)
+
+func TestClient_SubscribeToTimelineTransactions_StopsAtKnownPage(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "sync.json")
+
+	// First run: the executed transaction is written, the canceled one skipped
+	firstRun := bus.New()
+	state := syncstate.New(filePath)
+
+	firstRun.Subscribe(bus.TopicRowWritten, state.HandleDone)
+	firstRun.Subscribe(bus.TopicTransactionSkipped, state.HandleDone)
+
+	state.Seen(executedID, string(traderepublic.TimelineTransactionStatusEXECUTED))
+	state.Seen(canceledID, string(traderepublic.TimelineTransactionStatusCANCELED))
+	firstRun.Publish(bus.NewEvent(bus.TopicRowWritten, executedID, nil))
+	firstRun.Publish(bus.NewEvent(bus.TopicTransactionSkipped, canceledID, nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, firstRun.Tracker().Wait(ctx))
+	require.NoError(t, state.Save())
+
+	// Second run: the first page only holds known transactions, so the next one is not subscribed to
+	state, err := syncstate.Load(filePath)
+	require.NoError(t, err)
+
+	after := "next-page"
+	page, err := json.Marshal(traderepublic.TimelineTransactionsJson{
+		Cursors: traderepublic.TimelineTransactionsJsonCursors{After: &after},
+		Items: []traderepublic.TimelineTransaction{
+			timelineTransaction(executedID, traderepublic.TimelineTransactionStatusEXECUTED),
+			timelineTransaction(canceledID, traderepublic.TimelineTransactionStatusCANCELED),
+		},
+	})
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+
+	credentials := auth.NewMockCredentialsServiceInterface(ctrl)
+	credentials.EXPECT().GetToken().Return(auth.NewTokenWithValues("session", "refresh")).AnyTimes()
+
+	results := make(chan traderepublic.SubscriptionResult, 1)
+	results <- traderepublic.SubscriptionResult{Data: page}
+
+	wsClient := traderepublic.NewMockWSClientInterface(ctrl)
+	wsClient.EXPECT().Subscribe(gomock.Any()).Return(results, nil).Times(1)
+
+	secondRun := bus.New()
+	client := message.NewClient(secondRun, credentials, wsClient, message.NewLimiter(message.LimiterConfig{}))
+	client.SetKnownTransactions(state)
+
+	require.NoError(t, client.SubscribeToTimelineTransactions(ctx))
+	require.NoError(t, secondRun.Tracker().Wait(ctx))
+}
+
+func timelineTransaction(id string, status traderepublic.TimelineTransactionStatus) traderepublic.TimelineTransaction {
+	return traderepublic.TimelineTransaction{
+		Action: traderepublic.TimelineTransactionAction{
+			Payload: traderepublic.Uuid(id),
+			Type:    traderepublic.TimelineTransactionActionTypeTimelineDetail,
+		},
+		Amount:    traderepublic.TimelineTransactionAmount{Currency: "EUR", FractionDigits: 2, Value: -100},
+		Avatar:    traderepublic.TimelineTransactionAvatar{Asset: "logos/US6701002056/v2"},
+		EventType: traderepublic.TimelineTransactionEventTypeTradingTradeExecuted,
+		Icon:      "logos/US6701002056/v2",
+		Id:        traderepublic.Uuid(id),
+		Status:    status,
+		Timestamp: "2024-05-01T10:00:00.000+0000",
+		Title:     "NVIDIA",
+	}
+}
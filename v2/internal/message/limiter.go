@@ -0,0 +1,160 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultMaxInFlight       = 10
+	DefaultRequestsPerSecond = 5
+	DefaultJitter            = 100 * time.Millisecond
+)
+
+// LimiterConfig configures how subscriptions are spread, a zero value disables the respective limit.
+type LimiterConfig struct {
+	MaxInFlight       int           // Subscriptions waiting for their data at the same time
+	RequestsPerSecond float64       // Subscriptions sent per second
+	Jitter            time.Duration // Random delay added to every subscription
+}
+
+// LimiterStats is a snapshot of the subscriptions passing the limiter.
+type LimiterStats struct {
+	Queued    int64 // Waiting to be sent
+	InFlight  int64 // Sent and waiting for their data
+	Completed int64
+}
+
+// Limiter caps the subscriptions in flight and the rate they are sent at, so big accounts do not flood TR.
+type Limiter struct {
+	slots     chan struct{}
+	interval  time.Duration
+	jitter    time.Duration
+	next      time.Time
+	mu        sync.Mutex
+	queued    atomic.Int64
+	inFlight  atomic.Int64
+	completed atomic.Int64
+}
+
+// NewLimiter creates a new Limiter with the given configuration.
+func NewLimiter(config LimiterConfig) *Limiter {
+	limiter := &Limiter{
+		jitter: config.Jitter,
+	}
+
+	if config.MaxInFlight > 0 {
+		limiter.slots = make(chan struct{}, config.MaxInFlight)
+	}
+
+	if config.RequestsPerSecond > 0 {
+		limiter.interval = time.Duration(float64(time.Second) / config.RequestsPerSecond)
+	}
+
+	return limiter
+}
+
+// Acquire waits until a subscription may be sent, every successful call has to be followed by Release
+// once its data is received.
+func (l *Limiter) Acquire(ctx context.Context) error {
+	l.queued.Add(1)
+
+	defer l.queued.Add(-1)
+
+	if l.slots != nil {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("subscription not sent: %w", ctx.Err())
+		case l.slots <- struct{}{}:
+		}
+	}
+
+	timer := time.NewTimer(l.delay())
+
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.releaseSlot()
+
+		return fmt.Errorf("subscription not sent: %w", ctx.Err())
+	case <-timer.C:
+	}
+
+	l.inFlight.Add(1)
+
+	return nil
+}
+
+// Release frees the slot of a subscription that received its data or failed.
+func (l *Limiter) Release() {
+	l.inFlight.Add(-1)
+	l.completed.Add(1)
+	l.releaseSlot()
+}
+
+// Stats returns the current number of queued, in-flight and completed subscriptions.
+func (l *Limiter) Stats() LimiterStats {
+	return LimiterStats{
+		Queued:    l.queued.Load(),
+		InFlight:  l.inFlight.Load(),
+		Completed: l.completed.Load(),
+	}
+}
+
+// Report logs the stats on every tick until the context is done.
+func (l *Limiter) Report(ctx context.Context, ticks <-chan time.Time) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticks:
+			stats := l.Stats()
+
+			slog.Debug(
+				"subscription queue",
+				"queued", stats.Queued,
+				"in_flight", stats.InFlight,
+				"completed", stats.Completed,
+			)
+		}
+	}
+}
+
+// delay reserves the next point in time a subscription may be sent at and returns how long to wait for it.
+func (l *Limiter) delay() time.Duration {
+	var jitter time.Duration
+
+	if l.jitter > 0 {
+		jitter = rand.N(l.jitter)
+	}
+
+	if l.interval == 0 {
+		return jitter
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	at := l.next
+
+	if at.Before(now) {
+		at = now
+	}
+
+	l.next = at.Add(l.interval)
+
+	return at.Sub(now) + jitter
+}
+
+func (l *Limiter) releaseSlot() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
@@ -0,0 +1,69 @@
+package message_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_MaxInFlight(t *testing.T) {
+	t.Parallel()
+
+	limiter := message.NewLimiter(message.LimiterConfig{MaxInFlight: 2})
+
+	require.NoError(t, limiter.Acquire(context.Background()))
+	require.NoError(t, limiter.Acquire(context.Background()))
+	assert.Equal(t, message.LimiterStats{InFlight: 2}, limiter.Stats())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := limiter.Acquire(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	limiter.Release()
+
+	require.NoError(t, limiter.Acquire(context.Background()))
+	assert.Equal(t, message.LimiterStats{InFlight: 2, Completed: 1}, limiter.Stats())
+}
+
+func TestLimiter_RequestsPerSecond(t *testing.T) {
+	t.Parallel()
+
+	limiter := message.NewLimiter(message.LimiterConfig{RequestsPerSecond: 50})
+	start := time.Now()
+
+	for range 4 {
+		require.NoError(t, limiter.Acquire(context.Background()))
+	}
+
+	// The first subscription is sent at once, the following ones 20ms apart
+	assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+}
+
+func TestLimiter_Queued(t *testing.T) {
+	t.Parallel()
+
+	limiter := message.NewLimiter(message.LimiterConfig{MaxInFlight: 1})
+
+	require.NoError(t, limiter.Acquire(context.Background()))
+
+	acquired := make(chan error)
+
+	go func() {
+		acquired <- limiter.Acquire(context.Background())
+	}()
+
+	assert.Eventually(t, func() bool {
+		return limiter.Stats().Queued == 1
+	}, time.Second, time.Millisecond)
+
+	limiter.Release()
+
+	require.NoError(t, <-acquired)
+	assert.Equal(t, message.LimiterStats{InFlight: 1, Completed: 1}, limiter.Stats())
+}
@@ -0,0 +1,163 @@
+// Package profile namespaces the credentials, output and caches of the accounts downloaded from.
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal"
+)
+
+var ErrInvalidName = errors.New("invalid profile name")
+
+// namePattern restricts names to ones usable as directory names and keyring users.
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// defaultName is the name the default profile is shown as. It is reserved, a profile of that name would share
+// the keyring user of the default profile.
+const defaultName = "default"
+
+// Profile is a named account, the default profile has no name and keeps the files in the working directory.
+type Profile struct {
+	name string
+}
+
+// New creates a profile with the given name, an empty name returns the default profile.
+func New(name string) (Profile, error) {
+	if name != "" && !namePattern.MatchString(name) {
+		return Profile{}, fmt.Errorf("%w: %s", ErrInvalidName, name)
+	}
+
+	if name == defaultName {
+		return Profile{}, fmt.Errorf("%w: %s is reserved for the default profile", ErrInvalidName, name)
+	}
+
+	return Profile{name: name}, nil
+}
+
+// List returns the profiles that have been used before, the default profile first when its files are in the
+// working directory and the named ones sorted by name by os.ReadDir.
+func List() ([]Profile, error) {
+	var profiles []Profile
+
+	if (Profile{}).used() {
+		profiles = append(profiles, Profile{})
+	}
+
+	entries, err := os.ReadDir(internal.ProfilesBaseDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return profiles, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || !namePattern.MatchString(entry.Name()) || entry.Name() == defaultName {
+			continue
+		}
+
+		profiles = append(profiles, Profile{name: entry.Name()})
+	}
+
+	return profiles, nil
+}
+
+// Name returns the name of the profile, empty for the default profile.
+func (p Profile) Name() string {
+	return p.name
+}
+
+// String returns the name of the profile, "default" for the default profile.
+func (p Profile) String() string {
+	if p.name == "" {
+		return defaultName
+	}
+
+	return p.name
+}
+
+// Dir returns the directory the files of the profile are kept in.
+func (p Profile) Dir() string {
+	if p.name == "" {
+		return "."
+	}
+
+	return filepath.Join(internal.ProfilesBaseDir, p.name)
+}
+
+// Init creates the directory of the profile.
+func (p Profile) Init() error {
+	err := os.MkdirAll(p.Dir(), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("failed to create directory of profile %s: %w", p, err)
+	}
+
+	return nil
+}
+
+// AuthTokenFilename returns the file the credentials are stored in.
+func (p Profile) AuthTokenFilename() string {
+	return p.path(internal.AuthTokenFilename)
+}
+
+// EncryptedAuthTokenFilename returns the file the encrypted credentials are stored in.
+func (p Profile) EncryptedAuthTokenFilename() string {
+	return p.path(internal.EncryptedAuthTokenFilename)
+}
+
+// KeyringUser returns the user the credentials are stored under in the keyring.
+func (p Profile) KeyringUser() string {
+	return p.name
+}
+
+// CSVFilename returns the file the transactions are exported to.
+func (p Profile) CSVFilename() string {
+	return p.path(internal.CSVFilename)
+}
+
+// CheckpointFilename returns the file the state of the last run is saved in.
+func (p Profile) CheckpointFilename() string {
+	return p.path(internal.CheckpointFilename)
+}
+
+// SyncStateFilename returns the file the transactions downloaded so far are saved in.
+func (p Profile) SyncStateFilename() string {
+	return p.path(internal.SyncStateFilename)
+}
+
+// DeadLetterBaseDir returns the directory the events that failed processing are kept in.
+func (p Profile) DeadLetterBaseDir() string {
+	return p.path(internal.DeadLetterBaseDir)
+}
+
+// ResponseBaseDir returns the directory the raw responses are written to.
+func (p Profile) ResponseBaseDir() string {
+	return p.path(internal.ResponseBaseDir)
+}
+
+// used reports whether the credentials or the output of the profile have been written before, credentials
+// kept in the keyring leave the exported transactions behind.
+func (p Profile) used() bool {
+	for _, path := range []string{p.AuthTokenFilename(), p.EncryptedAuthTokenFilename(), p.CSVFilename()} {
+		_, err := os.Stat(path)
+		if err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// path places the default path inside the directory of the profile.
+func (p Profile) path(defaultPath string) string {
+	if p.name == "" {
+		return defaultPath
+	}
+
+	return filepath.Join(p.Dir(), defaultPath)
+}
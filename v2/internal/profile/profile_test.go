@@ -0,0 +1,110 @@
+package profile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		valid bool
+	}{
+		{name: "", valid: true},
+		{name: "alice", valid: true},
+		{name: "child_1-depot", valid: true},
+		{name: "../alice", valid: false},
+		{name: "alice bob", valid: false},
+		{name: "default", valid: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run("it validates name "+testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := profile.New(testCase.name)
+			if testCase.valid {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorIs(t, err, profile.ErrInvalidName)
+		})
+	}
+}
+
+func TestProfile_Paths(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default profile keeps files in working directory", func(t *testing.T) {
+		t.Parallel()
+
+		prof, err := profile.New("")
+		require.NoError(t, err)
+
+		assert.Equal(t, "default", prof.String())
+		assert.Equal(t, ".", prof.Dir())
+		assert.Equal(t, "./.auth", prof.AuthTokenFilename())
+		assert.Equal(t, "./transactions.csv", prof.CSVFilename())
+		assert.Equal(t, "./debug/responses", prof.ResponseBaseDir())
+		assert.Empty(t, prof.KeyringUser())
+	})
+
+	t.Run("named profile keeps files in its directory", func(t *testing.T) {
+		t.Parallel()
+
+		prof, err := profile.New("alice")
+		require.NoError(t, err)
+
+		assert.Equal(t, "alice", prof.String())
+		assert.Equal(t, "profiles/alice", prof.Dir())
+		assert.Equal(t, "profiles/alice/.auth", prof.AuthTokenFilename())
+		assert.Equal(t, "profiles/alice/.auth.age", prof.EncryptedAuthTokenFilename())
+		assert.Equal(t, "profiles/alice/transactions.csv", prof.CSVFilename())
+		assert.Equal(t, "profiles/alice/debug/responses", prof.ResponseBaseDir())
+		assert.Equal(t, "alice", prof.KeyringUser())
+	})
+}
+
+func TestList(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	profiles, err := profile.List()
+	require.NoError(t, err)
+	assert.Empty(t, profiles)
+
+	for _, name := range []string{"bob", "alice"} {
+		prof, err := profile.New(name)
+		require.NoError(t, err)
+		require.NoError(t, prof.Init())
+	}
+
+	err = os.WriteFile(filepath.Join("profiles", "notes.txt"), []byte("not a profile"), 0o600)
+	require.NoError(t, err)
+
+	err = os.Mkdir(filepath.Join("profiles", "default"), os.ModePerm)
+	require.NoError(t, err)
+
+	profiles, err = profile.List()
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.Equal(t, "alice", profiles[0].Name())
+	assert.Equal(t, "bob", profiles[1].Name())
+
+	err = os.WriteFile("transactions.csv", []byte("ID\n"), 0o600)
+	require.NoError(t, err)
+
+	profiles, err = profile.List()
+	require.NoError(t, err)
+	require.Len(t, profiles, 3)
+	assert.Equal(t, "default", profiles[0].String())
+	assert.Equal(t, "alice", profiles[1].Name())
+}
@@ -0,0 +1,144 @@
+// Package syncstate records the transactions downloaded by previous runs, so a run only fetches the new
+// and changed ones.
+package syncstate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/writer"
+)
+
+// State is the sync state stored after every completed run.
+type State struct {
+	LastSync     time.Time         `json:"lastSync"`
+	Transactions map[string]string `json:"transactions"` // Status of the synced transactions keyed by their ID
+}
+
+// Store keeps the sync state of a profile. Transactions become known once their rows are written or they are
+// skipped on purpose, e.g. ignored or canceled ones. Failed ones are fetched again by the next run.
+type Store struct {
+	filePath string
+	lastSync time.Time
+	known    map[string]string
+	seen     map[string]string // Statuses of the transactions not done with yet
+	mu       sync.RWMutex
+}
+
+// New creates an empty Store, so every transaction is fetched.
+func New(filePath string) *Store {
+	return &Store{
+		filePath: filePath,
+		known:    make(map[string]string),
+		seen:     make(map[string]string),
+	}
+}
+
+// Load reads the sync state of the previous runs, a missing file starts without one.
+func Load(filePath string) (*Store, error) {
+	store := New(filePath)
+
+	contents, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' file: %w", filePath, err)
+	}
+
+	var previous State
+
+	err = json.Unmarshal(contents, &previous)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse '%s' file: %w", filePath, err)
+	}
+
+	store.lastSync = previous.LastSync
+
+	for id, status := range previous.Transactions {
+		store.known[id] = status
+	}
+
+	return store, nil
+}
+
+// LastSync returns when the last completed run finished, zero if there was none.
+func (s *Store) LastSync() time.Time {
+	return s.lastSync
+}
+
+// Known reports whether the transaction was synced before with the same status.
+func (s *Store) Known(id, status string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	knownStatus, found := s.known[id]
+
+	return found && knownStatus == status
+}
+
+// Seen records the status of a transaction whose details are being fetched.
+func (s *Store) Seen(id, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seen[id] = status
+}
+
+// Skipped marks a transaction the run skips as known, e.g. one written by the interrupted run being resumed.
+func (s *Store) Skipped(id, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.known[id] = status
+	delete(s.seen, id)
+}
+
+// HandleDone marks the transaction of a bus.TopicRowWritten or bus.TopicTransactionSkipped event as known with
+// the status it was seen with. The state is only saved once the rows are flushed.
+func (s *Store) HandleDone(event bus.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status, found := s.seen[event.ID]
+	if !found {
+		return
+	}
+
+	s.known[event.ID] = status
+	delete(s.seen, event.ID)
+}
+
+// Save writes the sync state, replacing the previous one at once so it is never left half written.
+func (s *Store) Save() error {
+	s.mu.RLock()
+
+	state := State{
+		LastSync:     time.Now().UTC(),
+		Transactions: make(map[string]string, len(s.known)),
+	}
+
+	for id, status := range s.known {
+		state.Transactions[id] = status
+	}
+
+	s.mu.RUnlock()
+
+	contents, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync state: %w", err)
+	}
+
+	err = writer.WriteFileAtomic(s.filePath, contents)
+	if err != nil {
+		return fmt.Errorf("failed to write '%s' file: %w", s.filePath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package syncstate_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/syncstate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "sync.json")
+
+	store, err := syncstate.Load(filePath)
+	require.NoError(t, err)
+	assert.True(t, store.LastSync().IsZero())
+
+	store.Seen("written", "EXECUTED")
+	store.Seen("not-written", "EXECUTED")
+	store.Seen("canceled", "CANCELED")
+	store.HandleDone(bus.NewEvent(bus.TopicRowWritten, "written", nil))
+	store.HandleDone(bus.NewEvent(bus.TopicRowWritten, "not-seen", nil))
+	store.HandleDone(bus.NewEvent(bus.TopicTransactionSkipped, "canceled", nil))
+	store.Skipped("checkpoint", "EXECUTED")
+
+	// Not known before the state is saved and loaded by the next run
+	assert.False(t, store.Known("not-written", "EXECUTED"))
+
+	require.NoError(t, store.Save())
+
+	store, err = syncstate.Load(filePath)
+	require.NoError(t, err)
+	assert.False(t, store.LastSync().IsZero())
+
+	tests := []struct {
+		name   string
+		id     string
+		status string
+		known  bool
+	}{
+		{
+			name:   "Written transaction is known",
+			id:     "written",
+			status: "EXECUTED",
+			known:  true,
+		},
+		{
+			name:   "Changed status is not known",
+			id:     "written",
+			status: "CANCELED",
+			known:  false,
+		},
+		{
+			name:   "Skipped transaction is known",
+			id:     "canceled",
+			status: "CANCELED",
+			known:  true,
+		},
+		{
+			name:   "Transaction written by the resumed run is known",
+			id:     "checkpoint",
+			status: "EXECUTED",
+			known:  true,
+		},
+		{
+			name:   "Transaction without row is not known",
+			id:     "not-written",
+			status: "EXECUTED",
+			known:  false,
+		},
+		{
+			name:   "Transaction not seen is not known",
+			id:     "not-seen",
+			status: "EXECUTED",
+			known:  false,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.known, store.Known(testCase.id, testCase.status))
+		})
+	}
+}
+
+func TestNew_IgnoresPreviousState(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "sync.json")
+	require.NoError(t, os.WriteFile(filePath, []byte(`{"transactions":{"a":"EXECUTED"}}`), 0o600))
+
+	store := syncstate.New(filePath)
+	assert.False(t, store.Known("a", "EXECUTED"))
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "sync.json")
+	require.NoError(t, os.WriteFile(filePath, []byte("{"), 0o600))
+
+	_, err := syncstate.Load(filePath)
+	require.Error(t, err)
+}
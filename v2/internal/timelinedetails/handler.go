@@ -4,16 +4,17 @@ import (
 	"log/slog"
 
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/transaction"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
 )
 
 // Handler struct manages the handling of timeline details events.
 type Handler struct {
-	eventBus *bus.EventBus // EventBus to publish events
+	eventBus bus.EventBusInterface // EventBus to publish events
 }
 
 // NewHandler creates a new instance of Handler with the provided EventBus and Normalizer.
-func NewHandler(eventBus *bus.EventBus) *Handler {
+func NewHandler(eventBus bus.EventBusInterface) *Handler {
 	return &Handler{
 		eventBus: eventBus,
 	}
@@ -37,12 +38,17 @@ func (h *Handler) Handle(event bus.Event) {
 		return
 	}
 
-	// Check if there is an action payload in the header
-	if header.Action == nil {
-		return
-	}
+	// Instrument is linked in the action payload, income transactions only have it in the icon
+	var isin string
 
-	isin := header.Action.Payload
+	if header.Action != nil {
+		isin = header.Action.Payload
+	} else {
+		isin, err = transaction.ExtractInstrumentISINFromIcon(header.Data.Icon)
+		if err != nil {
+			return
+		}
+	}
 
 	// Publish a new event to fetch instrument details
 	h.eventBus.Publish(bus.NewEvent(bus.TopicInstrumentFetch, isin, nil))
@@ -5,19 +5,37 @@ import (
 	"log/slog"
 
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/checkpoint"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/message"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/syncstate"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
+	gocache "github.com/patrickmn/go-cache"
 )
 
 type Handler struct {
-	eventBus  *bus.EventBus
-	msgClient message.ClientInterface
+	ctx        context.Context // Run the details are subscribed for, waits for the rate limiter stop once it is done
+	eventBus   bus.EventBusInterface
+	msgClient  message.ClientInterface
+	eventTypes *gocache.Cache
+	checkpoint *checkpoint.Store
+	syncState  *syncstate.Store
 }
 
-func NewHandler(eventBus *bus.EventBus, msgClient message.ClientInterface) *Handler {
+func NewHandler(
+	ctx context.Context,
+	eventBus bus.EventBusInterface,
+	msgClient message.ClientInterface,
+	eventTypes *gocache.Cache,
+	checkpoint *checkpoint.Store,
+	syncState *syncstate.Store,
+) *Handler {
 	return &Handler{
-		eventBus:  eventBus,
-		msgClient: msgClient,
+		ctx:        ctx,
+		eventBus:   eventBus,
+		msgClient:  msgClient,
+		eventTypes: eventTypes,
+		checkpoint: checkpoint,
+		syncState:  syncState,
 	}
 }
 
@@ -30,7 +48,27 @@ func (h *Handler) Handle(event bus.Event) {
 	}
 
 	for _, transaction := range transactions.Items {
-		err := h.msgClient.SubscribeToTimelineDetailV2(context.Background(), transaction.Id)
+		// Written by the interrupted run being resumed
+		if h.checkpoint.Skip(string(transaction.Id)) {
+			slog.Debug("transaction already processed", "transaction_id", transaction.Id)
+			h.syncState.Skipped(string(transaction.Id), string(transaction.Status))
+
+			continue
+		}
+
+		// Downloaded by a previous run and unchanged since
+		if h.syncState.Known(string(transaction.Id), string(transaction.Status)) {
+			slog.Debug("transaction already synced", "transaction_id", transaction.Id)
+
+			continue
+		}
+
+		h.syncState.Seen(string(transaction.Id), string(transaction.Status))
+
+		// Event type is only listed here, the transaction handler needs it to resolve the type
+		h.eventTypes.Set(string(transaction.Id), string(transaction.EventType), gocache.NoExpiration)
+
+		err := h.msgClient.SubscribeToTimelineDetailV2(h.ctx, transaction.Id)
 		if err != nil {
 			slog.Error("failed to subscribe to timeline detail", "error", err, "transaction_id", transaction.Id)
 
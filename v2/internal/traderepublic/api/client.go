@@ -14,6 +14,15 @@ const (
 	statusCodeError = http.StatusBadRequest
 )
 
+// ErrUnauthorized is returned when the API rejects the provided credentials.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// LoginProcess is a started login waiting to be confirmed.
+type LoginProcess struct {
+	ProcessID          string
+	ConfirmationMethod traderepublic.APILoginResponseConfirmationMethod
+}
+
 // Client is a client that uses the generated OpenAPI client.
 type Client struct {
 	client *traderepublic.ClientWithResponses
@@ -21,6 +30,11 @@ type Client struct {
 
 // NewClient creates a new client that uses the generated OpenAPI client.
 func NewClient() (*Client, error) {
+	return NewClientWithServer(traderepublic.ServerUrlTradeRepublicRESTAPI)
+}
+
+// NewClientWithServer creates a new client sending the requests to the given server URL.
+func NewClientWithServer(server string) (*Client, error) {
 	// Create a request editor to add common headers
 	reqEditor := func(_ context.Context, req *http.Request) error {
 		req.Header.Set("User-Agent", traderepublic.HTTPUserAgent)
@@ -31,7 +45,7 @@ func NewClient() (*Client, error) {
 
 	// Create the client with the base URL and request editor
 	client, err := traderepublic.NewClientWithResponses(
-		traderepublic.ServerUrlTradeRepublicRESTAPI,
+		server,
 		traderepublic.WithRequestEditorFn(reqEditor),
 	)
 	if err != nil {
@@ -43,30 +57,66 @@ func NewClient() (*Client, error) {
 	}, nil
 }
 
-// Login logs in with phone number and PIN.
-func (c *Client) Login(requestBody traderepublic.APILoginRequest) (string, error) {
+// Login logs in with phone number and PIN, the login is confirmed with an OTP unless the response
+// asks for the confirmation in the paired mobile app.
+func (c *Client) Login(requestBody traderepublic.APILoginRequest) (LoginProcess, error) {
+	process := LoginProcess{
+		ConfirmationMethod: traderepublic.APILoginResponseConfirmationMethodSMS,
+	}
+
 	// Make the login request
 	resp, err := c.client.LoginWithResponse(context.Background(), requestBody)
 	if err != nil {
-		return "", fmt.Errorf("could not login: %w", err)
+		return process, fmt.Errorf("could not login: %w", err)
 	}
 
 	// Check for error response
 	if resp.StatusCode() >= statusCodeError {
-		return "", fmt.Errorf(
+		return process, fmt.Errorf(
 			"login failed with status code %d: %s",
 			resp.StatusCode(),
 			string(resp.Body),
 		)
 	}
 
-	// Extract the process ID from the response
-	var processID string
+	// Extract the process ID and confirmation method from the response
 	if resp.JSON200 != nil && resp.JSON200.ProcessId != nil {
-		processID = *resp.JSON200.ProcessId
+		process.ProcessID = *resp.JSON200.ProcessId
+	}
+
+	if resp.JSON200 != nil && resp.JSON200.ConfirmationMethod != nil {
+		process.ConfirmationMethod = *resp.JSON200.ConfirmationMethod
+	}
+
+	return process, nil
+}
+
+// GetLoginStatus returns the state of a login confirmed in the paired mobile app, the cookies carry
+// the session once it is confirmed.
+func (c *Client) GetLoginStatus(processID string) (traderepublic.APILoginStatusResponseStatus, []*http.Cookie, error) {
+	if processID == "" {
+		return "", nil, errors.New("processID cannot be empty")
+	}
+
+	resp, err := c.client.GetLoginStatusWithResponse(context.Background(), processID)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not get login status: %w", err)
+	}
+
+	// Check for error response
+	if resp.StatusCode() >= statusCodeError {
+		return "", nil, fmt.Errorf(
+			"login status request failed with status code %d: %s",
+			resp.StatusCode(),
+			string(resp.Body),
+		)
 	}
 
-	return processID, nil
+	if resp.JSON200 == nil {
+		return "", nil, errors.New("login status response contains no status")
+	}
+
+	return resp.JSON200.Status, resp.HTTPResponse.Cookies(), nil
 }
 
 // PostOTP verifies the OTP.
@@ -93,3 +143,37 @@ func (c *Client) PostOTP(processID, otp string) ([]*http.Cookie, error) {
 	// Return all cookies from the response
 	return resp.HTTPResponse.Cookies(), nil
 }
+
+// RefreshSession renews the session using the refresh token.
+func (c *Client) RefreshSession(refreshToken string) ([]*http.Cookie, error) {
+	if refreshToken == "" {
+		return nil, errors.New("refresh token cannot be empty")
+	}
+
+	// Refresh token is sent as a cookie
+	withRefreshToken := func(_ context.Context, req *http.Request) error {
+		req.AddCookie(&http.Cookie{Name: "tr_refresh", Value: refreshToken})
+
+		return nil
+	}
+
+	resp, err := c.client.RefreshSessionWithResponse(context.Background(), withRefreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("could not refresh session: %w", err)
+	}
+
+	if resp.StatusCode() == http.StatusUnauthorized || resp.StatusCode() == http.StatusForbidden {
+		return nil, fmt.Errorf("session refresh failed: %w", ErrUnauthorized)
+	}
+
+	// Check for error response
+	if resp.StatusCode() >= statusCodeError {
+		return nil, fmt.Errorf(
+			"session refresh failed with status code %d: %s",
+			resp.StatusCode(),
+			string(resp.Body),
+		)
+	}
+
+	return resp.HTTPResponse.Cookies(), nil
+}
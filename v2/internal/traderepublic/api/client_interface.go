@@ -11,8 +11,14 @@ import (
 // ClientInterface is the interface for the Trade Republic API client.
 type ClientInterface interface {
 	// Login logs in with phone number and PIN.
-	Login(requestBody traderepublic.APILoginRequest) (string, error)
+	Login(requestBody traderepublic.APILoginRequest) (LoginProcess, error)
+
+	// GetLoginStatus returns the state of a login confirmed in the paired mobile app.
+	GetLoginStatus(processID string) (traderepublic.APILoginStatusResponseStatus, []*http.Cookie, error)
 
 	// PostOTP verifies the OTP.
 	PostOTP(processID, otp string) ([]*http.Cookie, error)
+
+	// RefreshSession renews the session using the refresh token.
+	RefreshSession(refreshToken string) ([]*http.Cookie, error)
 }
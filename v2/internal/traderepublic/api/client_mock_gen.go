@@ -41,11 +41,27 @@ func (m *MockClientInterface) EXPECT() *MockClientInterfaceMockRecorder {
 	return m.recorder
 }
 
+// GetLoginStatus mocks base method.
+func (m *MockClientInterface) GetLoginStatus(processID string) (traderepublic.APILoginStatusResponseStatus, []*http.Cookie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginStatus", processID)
+	ret0, _ := ret[0].(traderepublic.APILoginStatusResponseStatus)
+	ret1, _ := ret[1].([]*http.Cookie)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetLoginStatus indicates an expected call of GetLoginStatus.
+func (mr *MockClientInterfaceMockRecorder) GetLoginStatus(processID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginStatus", reflect.TypeOf((*MockClientInterface)(nil).GetLoginStatus), processID)
+}
+
 // Login mocks base method.
-func (m *MockClientInterface) Login(requestBody traderepublic.APILoginRequest) (string, error) {
+func (m *MockClientInterface) Login(requestBody traderepublic.APILoginRequest) (LoginProcess, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Login", requestBody)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(LoginProcess)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -70,3 +86,18 @@ func (mr *MockClientInterfaceMockRecorder) PostOTP(processID, otp any) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostOTP", reflect.TypeOf((*MockClientInterface)(nil).PostOTP), processID, otp)
 }
+
+// RefreshSession mocks base method.
+func (m *MockClientInterface) RefreshSession(refreshToken string) ([]*http.Cookie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshSession", refreshToken)
+	ret0, _ := ret[0].([]*http.Cookie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshSession indicates an expected call of RefreshSession.
+func (mr *MockClientInterfaceMockRecorder) RefreshSession(refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshSession", reflect.TypeOf((*MockClientInterface)(nil).RefreshSession), refreshToken)
+}
@@ -5,39 +5,78 @@ package auth
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/console"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/traderepublic/api"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
 )
 
+const (
+	// DefaultConfirmationPollInterval is how often the status of a login confirmed in the app is checked.
+	DefaultConfirmationPollInterval = 2 * time.Second
+	// DefaultConfirmationTimeout is how long the login is waited to be confirmed in the app.
+	DefaultConfirmationTimeout = 2 * time.Minute
+)
+
+var (
+	ErrLoginRejected       = errors.New("login rejected in the app")
+	ErrLoginExpired        = errors.New("login expired")
+	ErrConfirmationTimeout = errors.New("login not confirmed in time")
+)
+
 type Client struct {
-	inputHandler console.InputHandlerInterface
-	apiClient    api.ClientInterface
-	mu           sync.Mutex
+	inputHandler        console.InputHandlerInterface
+	apiClient           api.ClientInterface
+	pollInterval        time.Duration
+	confirmationTimeout time.Duration
+	mu                  sync.Mutex
 }
 
 func NewClient(inputHandler console.InputHandlerInterface, apiClient api.ClientInterface) *Client {
 	return &Client{
-		inputHandler: inputHandler,
-		apiClient:    apiClient,
+		inputHandler:        inputHandler,
+		apiClient:           apiClient,
+		pollInterval:        DefaultConfirmationPollInterval,
+		confirmationTimeout: DefaultConfirmationTimeout,
 	}
 }
 
+// SetConfirmationPolling configures how often and how long a login confirmed in the app is polled for.
+func (c *Client) SetConfirmationPolling(interval, timeout time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pollInterval = interval
+	c.confirmationTimeout = timeout
+}
+
+// Login logs in with phone number and PIN, followed by the confirmation TR asks for: the OTP or the
+// push notification of the paired mobile app.
 func (c *Client) Login() (Token, error) {
 	var token Token
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	processID, err := c.ObtainProcessID()
+	process, err := c.StartLogin()
 	if err != nil {
 		return token, fmt.Errorf("could not obtain process ID: %w", err)
 	}
 
-	token, err = c.ProvideOTP(processID)
+	if process.ConfirmationMethod == traderepublic.APILoginResponseConfirmationMethodAPP {
+		token, err = c.AwaitAppConfirmation(ProcessID(process.ProcessID))
+		if err != nil {
+			return token, fmt.Errorf("could not confirm login in app: %w", err)
+		}
+
+		return token, nil
+	}
+
+	token, err = c.ProvideOTP(ProcessID(process.ProcessID))
 	if err != nil {
 		return token, fmt.Errorf("could not provide OTP: %w", err)
 	}
@@ -46,14 +85,24 @@ func (c *Client) Login() (Token, error) {
 }
 
 func (c *Client) ObtainProcessID() (ProcessID, error) {
+	process, err := c.StartLogin()
+	if err != nil {
+		return "", err
+	}
+
+	return ProcessID(process.ProcessID), nil
+}
+
+// StartLogin logs in with phone number and PIN, returning the process to confirm.
+func (c *Client) StartLogin() (api.LoginProcess, error) {
 	phoneNumber, err := c.inputHandler.GetPhoneNumber()
 	if err != nil {
-		return "", fmt.Errorf("failed to get phone number: %w", err)
+		return api.LoginProcess{}, fmt.Errorf("failed to get phone number: %w", err)
 	}
 
 	pin, err := c.inputHandler.GetPIN()
 	if err != nil {
-		return "", fmt.Errorf("failed to get PIN: %w", err)
+		return api.LoginProcess{}, fmt.Errorf("failed to get PIN: %w", err)
 	}
 
 	// Create the login request
@@ -63,12 +112,12 @@ func (c *Client) ObtainProcessID() (ProcessID, error) {
 	}
 
 	// Call the API client's Login method
-	processID, err := c.apiClient.Login(request)
+	process, err := c.apiClient.Login(request)
 	if err != nil {
-		return "", fmt.Errorf("could not login: %w", err)
+		return process, fmt.Errorf("could not login: %w", err)
 	}
 
-	return ProcessID(processID), nil
+	return process, nil
 }
 
 func (c *Client) ProvideOTP(processID ProcessID) (Token, error) {
@@ -93,6 +142,47 @@ func (c *Client) ProvideOTP(processID ProcessID) (Token, error) {
 	return ExtractTokenFromCookies(cookies), nil
 }
 
+// AwaitAppConfirmation polls the status of the login until it is confirmed in the paired mobile app,
+// rejected, expired or the confirmation timeout passes.
+func (c *Client) AwaitAppConfirmation(processID ProcessID) (Token, error) {
+	var token Token
+
+	if processID == "" {
+		return token, errors.New("processID cannot be empty")
+	}
+
+	slog.Info("Please confirm the login in the Trade Republic app")
+
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+
+	timeout := time.NewTimer(c.confirmationTimeout)
+	defer timeout.Stop()
+
+	for {
+		status, cookies, err := c.apiClient.GetLoginStatus(string(processID))
+		if err != nil {
+			return token, fmt.Errorf("could not get login status: %w", err)
+		}
+
+		switch status {
+		case traderepublic.APILoginStatusResponseStatusCONFIRMED:
+			return ExtractTokenFromCookies(cookies), nil
+		case traderepublic.APILoginStatusResponseStatusREJECTED:
+			return token, ErrLoginRejected
+		case traderepublic.APILoginStatusResponseStatusEXPIRED:
+			return token, ErrLoginExpired
+		case traderepublic.APILoginStatusResponseStatusPENDING:
+		}
+
+		select {
+		case <-timeout.C:
+			return token, ErrConfirmationTimeout
+		case <-ticker.C:
+		}
+	}
+}
+
 // ExtractTokenFromCookies creates a Token from HTTP cookies.
 func ExtractTokenFromCookies(cookies []*http.Cookie) Token {
 	var sessionValue, refreshValue string
@@ -0,0 +1,164 @@
+package auth_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/console"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/traderepublic/api"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/traderepublic/auth"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fakeProcessID = "process-1"
+
+// fakeLoginServer serves the login endpoints, the login is confirmed with the given method and, for the
+// app, reports the given final status after a pending poll.
+func fakeLoginServer(t *testing.T, method, status string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	polls := &atomic.Int32{}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /api/v1/auth/web/login", func(w http.ResponseWriter, r *http.Request) {
+		var request traderepublic.APILoginRequest
+
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil || request.PhoneNumber != "+491234567890" || request.Pin != "1234" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"processId":"` + fakeProcessID + `","countdownInSeconds":120,"confirmationMethod":"` + method + `"}`))
+	})
+
+	mux.HandleFunc("GET /api/v1/auth/web/login/{processId}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("processId") != fakeProcessID {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		current := status
+		if polls.Add(1) == 1 {
+			current = "PENDING"
+		}
+
+		if current == "CONFIRMED" {
+			http.SetCookie(w, &http.Cookie{Name: "tr_session", Value: "app-session"})
+			http.SetCookie(w, &http.Cookie{Name: "tr_refresh", Value: "app-refresh"})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"` + current + `"}`))
+	})
+
+	mux.HandleFunc("POST /api/v1/auth/web/login/{processId}/{otp}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("processId") != fakeProcessID || r.PathValue("otp") != "5678" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: "tr_session", Value: "otp-session"})
+		http.SetCookie(w, &http.Cookie{Name: "tr_refresh", Value: "otp-refresh"})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, polls
+}
+
+func newFakeAuthClient(t *testing.T, server *httptest.Server, otp string) *auth.Client {
+	t.Helper()
+
+	credentialsFile := filepath.Join(t.TempDir(), "credentials.env")
+
+	err := os.WriteFile(credentialsFile, []byte("TR_PHONE_NUMBER=+491234567890\nTR_PIN=1234\n"), 0o600)
+	require.NoError(t, err)
+
+	inputHandler, err := console.NewScriptedInputHandler(
+		credentialsFile,
+		console.NewStdinOTPSource(strings.NewReader(otp)),
+		time.Second,
+	)
+	require.NoError(t, err)
+
+	apiClient, err := api.NewClientWithServer(server.URL + "/api/v1")
+	require.NoError(t, err)
+
+	client := auth.NewClient(inputHandler, apiClient)
+	client.SetConfirmationPolling(10*time.Millisecond, time.Second)
+
+	return client
+}
+
+func TestClient_Login(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it confirms the login in the app", func(t *testing.T) {
+		t.Parallel()
+
+		server, polls := fakeLoginServer(t, "APP", "CONFIRMED")
+
+		token, err := newFakeAuthClient(t, server, "").Login()
+		require.NoError(t, err)
+		assert.Equal(t, "app-session", token.Session())
+		assert.Equal(t, "app-refresh", token.Refresh())
+		assert.Equal(t, int32(2), polls.Load())
+	})
+
+	t.Run("it provides the OTP", func(t *testing.T) {
+		t.Parallel()
+
+		server, polls := fakeLoginServer(t, "SMS", "CONFIRMED")
+
+		token, err := newFakeAuthClient(t, server, "5678\n").Login()
+		require.NoError(t, err)
+		assert.Equal(t, "otp-session", token.Session())
+		assert.Equal(t, "otp-refresh", token.Refresh())
+		assert.Zero(t, polls.Load())
+	})
+
+	t.Run("it reports a rejected login", func(t *testing.T) {
+		t.Parallel()
+
+		server, _ := fakeLoginServer(t, "APP", "REJECTED")
+
+		_, err := newFakeAuthClient(t, server, "").Login()
+		assert.ErrorIs(t, err, auth.ErrLoginRejected)
+	})
+
+	t.Run("it reports an expired login", func(t *testing.T) {
+		t.Parallel()
+
+		server, _ := fakeLoginServer(t, "APP", "EXPIRED")
+
+		_, err := newFakeAuthClient(t, server, "").Login()
+		assert.ErrorIs(t, err, auth.ErrLoginExpired)
+	})
+
+	t.Run("it gives up after the timeout", func(t *testing.T) {
+		t.Parallel()
+
+		server, _ := fakeLoginServer(t, "APP", "PENDING")
+
+		client := newFakeAuthClient(t, server, "")
+		client.SetConfirmationPolling(10*time.Millisecond, 50*time.Millisecond)
+
+		_, err := client.Login()
+		assert.ErrorIs(t, err, auth.ErrConfirmationTimeout)
+	})
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal"
 	"github.com/joho/godotenv"
@@ -16,6 +17,7 @@ const (
 type FileCredentialsService struct {
 	filePath string
 	token    Token
+	mu       sync.RWMutex
 }
 
 // NewFileCredentialsService creates a new file-based credentials service.
@@ -37,6 +39,10 @@ func (s *FileCredentialsService) Load() error {
 		return fmt.Errorf("failed to read '%s' file: %w", s.filePath, err)
 	}
 
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
 	s.token = NewTokenWithValues(env[sessionTokenKey], env[refreshTokenKey])
 
 	return nil
@@ -44,6 +50,10 @@ func (s *FileCredentialsService) Load() error {
 
 // Store stores credentials to a file.
 func (s *FileCredentialsService) Store(token Token) error {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
 	s.token = token
 
 	if err := godotenv.Write(map[string]string{
@@ -58,5 +68,9 @@ func (s *FileCredentialsService) Store(token Token) error {
 
 // GetToken returns the current token.
 func (s *FileCredentialsService) GetToken() Token {
+	s.mu.RLock()
+
+	defer s.mu.RUnlock()
+
 	return s.token
 }
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"filippo.io/age"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal"
+	"github.com/joho/godotenv"
+)
+
+var (
+	ErrEmptyPassphrase = errors.New("passphrase cannot be empty")
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+)
+
+// PassphraseFunc returns the passphrase the credentials are encrypted with.
+type PassphraseFunc func() (string, error)
+
+// EncryptedFileCredentialsService implements CredentialsServiceInterface using a file encrypted
+// with a passphrase, the file is an age file protected by an scrypt recipient.
+type EncryptedFileCredentialsService struct {
+	filePath   string
+	passphrase PassphraseFunc
+	secret     string // Passphrase requested once and reused for subsequent stores
+	token      Token
+	mu         sync.RWMutex
+}
+
+// NewEncryptedFileCredentialsService creates a new encrypted file-based credentials service.
+// If filePath is empty, it uses the default path from internal.EncryptedAuthTokenFilename.
+func NewEncryptedFileCredentialsService(filePath string, passphrase PassphraseFunc) *EncryptedFileCredentialsService {
+	if filePath == "" {
+		filePath = internal.EncryptedAuthTokenFilename
+	}
+
+	return &EncryptedFileCredentialsService{
+		filePath:   filePath,
+		passphrase: passphrase,
+	}
+}
+
+// Load decrypts credentials from a file.
+func (s *EncryptedFileCredentialsService) Load() error {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	contents, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' file: %w", s.filePath, err)
+	}
+
+	secret, err := s.getPassphrase()
+	if err != nil {
+		return err
+	}
+
+	identity, err := age.NewScryptIdentity(secret)
+	if err != nil {
+		return fmt.Errorf("failed to create identity: %w", err)
+	}
+
+	reader, err := age.Decrypt(bytes.NewReader(contents), identity)
+
+	var noMatch *age.NoIdentityMatchError
+	if errors.As(err, &noMatch) {
+		return fmt.Errorf("failed to decrypt '%s' file: %w: %w", s.filePath, ErrWrongPassphrase, err)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to decrypt '%s' file: %w", s.filePath, err)
+	}
+
+	env, err := godotenv.Parse(reader)
+	if err != nil {
+		return fmt.Errorf("failed to parse '%s' file: %w", s.filePath, err)
+	}
+
+	s.token = NewTokenWithValues(env[sessionTokenKey], env[refreshTokenKey])
+
+	return nil
+}
+
+// Store encrypts credentials to a file.
+func (s *EncryptedFileCredentialsService) Store(token Token) error {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	s.token = token
+
+	secret, err := s.getPassphrase()
+	if err != nil {
+		return err
+	}
+
+	recipient, err := age.NewScryptRecipient(secret)
+	if err != nil {
+		return fmt.Errorf("failed to create recipient: %w", err)
+	}
+
+	content, err := godotenv.Marshal(map[string]string{
+		sessionTokenKey: token.Session(),
+		refreshTokenKey: token.Refresh(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal tokens: %w", err)
+	}
+
+	var encrypted bytes.Buffer
+
+	writer, err := age.Encrypt(&encrypted, recipient)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt tokens: %w", err)
+	}
+
+	_, err = io.WriteString(writer, content)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt tokens: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("failed to encrypt tokens: %w", err)
+	}
+
+	err = os.WriteFile(s.filePath, encrypted.Bytes(), 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write tokens to '%s' file: %w", s.filePath, err)
+	}
+
+	return nil
+}
+
+// GetToken returns the current token.
+func (s *EncryptedFileCredentialsService) GetToken() Token {
+	s.mu.RLock()
+
+	defer s.mu.RUnlock()
+
+	return s.token
+}
+
+// getPassphrase requests the passphrase on first use, the caller has to hold the lock.
+func (s *EncryptedFileCredentialsService) getPassphrase() (string, error) {
+	if s.secret != "" {
+		return s.secret, nil
+	}
+
+	secret, err := s.passphrase()
+	if err != nil {
+		return "", fmt.Errorf("failed to get passphrase: %w", err)
+	}
+
+	if secret == "" {
+		return "", ErrEmptyPassphrase
+	}
+
+	s.secret = secret
+
+	return secret, nil
+}
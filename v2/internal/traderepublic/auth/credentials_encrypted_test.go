@@ -0,0 +1,64 @@
+package auth_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/traderepublic/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func passphrase(secret string) auth.PassphraseFunc {
+	return func() (string, error) {
+		return secret, nil
+	}
+}
+
+func TestEncryptedFileCredentialsService(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Store and Load", func(t *testing.T) {
+		t.Parallel()
+
+		testFile := filepath.Join(t.TempDir(), "store-and-load.age")
+		testToken := auth.NewTokenWithValues("test-session-token", "test-refresh-token")
+
+		err := auth.NewEncryptedFileCredentialsService(testFile, passphrase("secret")).Store(testToken)
+		require.NoError(t, err, "Storing credentials should not error")
+
+		contents, err := os.ReadFile(testFile)
+		require.NoError(t, err)
+		assert.NotContains(t, string(contents), "test-refresh-token", "Tokens should not be stored in plaintext")
+
+		service := auth.NewEncryptedFileCredentialsService(testFile, passphrase("secret"))
+
+		err = service.Load()
+		require.NoError(t, err, "Loading credentials should not error")
+		assert.Equal(t, testToken, service.GetToken())
+	})
+
+	t.Run("Wrong passphrase", func(t *testing.T) {
+		t.Parallel()
+
+		testFile := filepath.Join(t.TempDir(), "wrong-passphrase.age")
+
+		err := auth.NewEncryptedFileCredentialsService(testFile, passphrase("secret")).
+			Store(auth.NewTokenWithValues("session", "refresh"))
+		require.NoError(t, err)
+
+		err = auth.NewEncryptedFileCredentialsService(testFile, passphrase("guess")).Load()
+		assert.ErrorIs(t, err, auth.ErrWrongPassphrase, "Loading with a wrong passphrase should error")
+	})
+
+	t.Run("Empty passphrase", func(t *testing.T) {
+		t.Parallel()
+
+		testFile := filepath.Join(t.TempDir(), "empty-passphrase.age")
+
+		err := auth.NewEncryptedFileCredentialsService(testFile, passphrase("")).
+			Store(auth.NewTokenWithValues("session", "refresh"))
+		assert.ErrorIs(t, err, auth.ErrEmptyPassphrase)
+	})
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/joho/godotenv"
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	// KeyringService is the service name the credentials are stored under in the keyring.
+	KeyringService = "traderepublic-portfolio-downloader"
+
+	defaultKeyringUser = "default"
+)
+
+// KeyringCredentialsService implements CredentialsServiceInterface using the OS keyring, on Linux
+// the Secret Service API is used over D-Bus.
+type KeyringCredentialsService struct {
+	user  string
+	token Token
+	mu    sync.RWMutex
+}
+
+// NewKeyringCredentialsService creates a new keyring-based credentials service.
+// If user is empty, the credentials are stored under the default user.
+func NewKeyringCredentialsService(user string) *KeyringCredentialsService {
+	if user == "" {
+		user = defaultKeyringUser
+	}
+
+	return &KeyringCredentialsService{
+		user: user,
+	}
+}
+
+// Load loads credentials from the keyring.
+func (s *KeyringCredentialsService) Load() error {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	secret, err := keyring.Get(KeyringService, s.user)
+	if err != nil {
+		return fmt.Errorf("failed to read credentials of '%s' from keyring: %w", s.user, err)
+	}
+
+	env, err := godotenv.Unmarshal(secret)
+	if err != nil {
+		return fmt.Errorf("failed to parse credentials of '%s': %w", s.user, err)
+	}
+
+	s.token = NewTokenWithValues(env[sessionTokenKey], env[refreshTokenKey])
+
+	return nil
+}
+
+// Store stores credentials to the keyring.
+func (s *KeyringCredentialsService) Store(token Token) error {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	s.token = token
+
+	secret, err := godotenv.Marshal(map[string]string{
+		sessionTokenKey: token.Session(),
+		refreshTokenKey: token.Refresh(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal tokens: %w", err)
+	}
+
+	err = keyring.Set(KeyringService, s.user, secret)
+	if err != nil {
+		return fmt.Errorf("failed to write credentials of '%s' to keyring: %w", s.user, err)
+	}
+
+	return nil
+}
+
+// GetToken returns the current token.
+func (s *KeyringCredentialsService) GetToken() Token {
+	s.mu.RLock()
+
+	defer s.mu.RUnlock()
+
+	return s.token
+}
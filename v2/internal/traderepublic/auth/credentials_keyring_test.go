@@ -0,0 +1,28 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/traderepublic/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestKeyringCredentialsService(t *testing.T) {
+	keyring.MockInit()
+
+	testToken := auth.NewTokenWithValues("test-session-token", "test-refresh-token")
+
+	err := auth.NewKeyringCredentialsService("store-and-load").Store(testToken)
+	require.NoError(t, err, "Storing credentials should not error")
+
+	service := auth.NewKeyringCredentialsService("store-and-load")
+
+	err = service.Load()
+	require.NoError(t, err, "Loading credentials should not error")
+	assert.Equal(t, testToken, service.GetToken())
+
+	err = auth.NewKeyringCredentialsService("missing").Load()
+	assert.Error(t, err, "Loading missing credentials should error")
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/traderepublic/api"
+)
+
+var (
+	ErrNoRefreshToken = errors.New("no refresh token available")
+	ErrSessionExpired = errors.New("session expired")
+)
+
+// Refresher keeps the session alive by renewing it with the refresh token before it expires.
+type Refresher struct {
+	apiClient          api.ClientInterface
+	credentialsService CredentialsServiceInterface
+	ticks              <-chan time.Time
+	mu                 sync.Mutex
+}
+
+// NewRefresher creates a new instance of Refresher renewing the session on every tick.
+func NewRefresher(
+	apiClient api.ClientInterface,
+	credentialsService CredentialsServiceInterface,
+	ticks <-chan time.Time,
+) *Refresher {
+	return &Refresher{
+		apiClient:          apiClient,
+		credentialsService: credentialsService,
+		ticks:              ticks,
+	}
+}
+
+// Run renews the session on every tick until the context is done.
+func (r *Refresher) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.ticks:
+			_, err := r.Refresh()
+			if err != nil {
+				slog.Error("failed to refresh session", "error", err)
+
+				continue
+			}
+
+			slog.Debug("session refreshed")
+		}
+	}
+}
+
+// Refresh renews the session and stores the new token, the refresh token is kept when the response
+// does not rotate it. ErrSessionExpired is returned when the refresh token is no longer accepted.
+func (r *Refresher) Refresh() (Token, error) {
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	current := r.credentialsService.GetToken()
+	if current.Refresh() == "" {
+		return current, ErrNoRefreshToken
+	}
+
+	cookies, err := r.apiClient.RefreshSession(current.Refresh())
+	if errors.Is(err, api.ErrUnauthorized) {
+		return current, fmt.Errorf("%w: %w", ErrSessionExpired, err)
+	}
+
+	if err != nil {
+		return current, fmt.Errorf("could not refresh session: %w", err)
+	}
+
+	renewed := ExtractTokenFromCookies(cookies)
+	if renewed.Session() == "" {
+		return current, errors.New("session refresh response contains no session token")
+	}
+
+	if renewed.Refresh() == "" {
+		renewed = NewTokenWithValues(renewed.Session(), current.Refresh())
+	}
+
+	err = r.credentialsService.Store(renewed)
+	if err != nil {
+		return current, fmt.Errorf("failed to store refreshed credentials: %w", err)
+	}
+
+	return renewed, nil
+}
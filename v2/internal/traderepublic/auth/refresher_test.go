@@ -0,0 +1,120 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/traderepublic/api"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/traderepublic/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRefresher_Refresh(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it stores the renewed session", func(t *testing.T) {
+		t.Parallel()
+
+		apiClient := api.NewMockClientInterface(gomock.NewController(t))
+		service := auth.NewFileCredentialsService(filepath.Join(t.TempDir(), "renewed.env"))
+
+		err := service.Store(auth.NewTokenWithValues("old-session", "refresh"))
+		require.NoError(t, err)
+
+		apiClient.EXPECT().RefreshSession("refresh").Return([]*http.Cookie{
+			{Name: "tr_session", Value: "new-session"},
+		}, nil)
+
+		token, err := auth.NewRefresher(apiClient, service, nil).Refresh()
+		require.NoError(t, err)
+		assert.Equal(t, "new-session", token.Session())
+		assert.Equal(t, "refresh", token.Refresh())
+
+		err = service.Load()
+		require.NoError(t, err)
+		assert.Equal(t, token, service.GetToken())
+	})
+
+	t.Run("it keeps the session on failure", func(t *testing.T) {
+		t.Parallel()
+
+		apiClient := api.NewMockClientInterface(gomock.NewController(t))
+		service := auth.NewFileCredentialsService(filepath.Join(t.TempDir(), "failed.env"))
+
+		err := service.Store(auth.NewTokenWithValues("old-session", "refresh"))
+		require.NoError(t, err)
+
+		apiClient.EXPECT().RefreshSession("refresh").Return(nil, errors.New("unauthorized"))
+
+		_, err = auth.NewRefresher(apiClient, service, nil).Refresh()
+		require.Error(t, err)
+		assert.Equal(t, "old-session", service.GetToken().Session())
+	})
+
+	t.Run("it reports expired session", func(t *testing.T) {
+		t.Parallel()
+
+		apiClient := api.NewMockClientInterface(gomock.NewController(t))
+		service := auth.NewFileCredentialsService(filepath.Join(t.TempDir(), "expired.env"))
+
+		err := service.Store(auth.NewTokenWithValues("old-session", "stale"))
+		require.NoError(t, err)
+
+		apiClient.EXPECT().RefreshSession("stale").Return(nil, fmt.Errorf("session refresh failed: %w", api.ErrUnauthorized))
+
+		_, err = auth.NewRefresher(apiClient, service, nil).Refresh()
+		assert.ErrorIs(t, err, auth.ErrSessionExpired)
+	})
+
+	t.Run("it requires a refresh token", func(t *testing.T) {
+		t.Parallel()
+
+		apiClient := api.NewMockClientInterface(gomock.NewController(t))
+		service := auth.NewFileCredentialsService(filepath.Join(t.TempDir(), "missing.env"))
+
+		_, err := auth.NewRefresher(apiClient, service, nil).Refresh()
+		assert.ErrorIs(t, err, auth.ErrNoRefreshToken)
+	})
+}
+
+func TestRefresher_Run(t *testing.T) {
+	t.Parallel()
+
+	apiClient := api.NewMockClientInterface(gomock.NewController(t))
+	service := auth.NewFileCredentialsService(filepath.Join(t.TempDir(), "run.env"))
+
+	err := service.Store(auth.NewTokenWithValues("old-session", "refresh"))
+	require.NoError(t, err)
+
+	refreshed := make(chan struct{})
+
+	apiClient.EXPECT().RefreshSession("refresh").DoAndReturn(func(string) ([]*http.Cookie, error) {
+		close(refreshed)
+
+		return []*http.Cookie{{Name: "tr_session", Value: "new-session"}}, nil
+	})
+
+	ticks := make(chan time.Time)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		auth.NewRefresher(apiClient, service, ticks).Run(ctx)
+		close(done)
+	}()
+
+	ticks <- time.Now()
+	<-refreshed
+
+	cancel()
+	<-done
+
+	assert.Equal(t, "new-session", service.GetToken().Session())
+}
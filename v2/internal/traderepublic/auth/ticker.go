@@ -6,5 +6,8 @@ import (
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal"
 )
 
-//nolint:gochecknoglobals
-var SessionRefreshTicker = time.NewTicker(internal.SessionRefreshInterval * time.Second)
+// NewSessionRefreshTicker creates a ticker firing whenever the session has to be refreshed to keep it alive,
+// the caller stops it once the run is over.
+func NewSessionRefreshTicker() *time.Ticker {
+	return time.NewTicker(internal.SessionRefreshInterval * time.Second)
+}
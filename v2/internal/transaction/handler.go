@@ -2,62 +2,103 @@ package transaction
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
+	gocache "github.com/patrickmn/go-cache"
 )
 
+// AttributeEventType is the attribute of a dead letter holding the event type of the timeline transaction.
+const AttributeEventType = "eventType"
+
 type Handler struct {
-	resolver *TypeResolver
-	mapper   *DataMapper
-	eventBus *bus.EventBus
+	resolver   *TypeResolver
+	mapper     *DataMapper
+	eventBus   bus.EventBusInterface
+	eventTypes *gocache.Cache // Event types of the timeline transactions keyed by their ID
 }
 
-func NewHandler(resolver *TypeResolver, mapper *DataMapper, eventBus *bus.EventBus) *Handler {
+func NewHandler(resolver *TypeResolver, mapper *DataMapper, eventBus bus.EventBusInterface, eventTypes *gocache.Cache) *Handler {
 	return &Handler{
-		resolver: resolver,
-		mapper:   mapper,
-		eventBus: eventBus,
+		resolver:   resolver,
+		mapper:     mapper,
+		eventBus:   eventBus,
+		eventTypes: eventTypes,
 	}
 }
 
 func (h *Handler) Handle(event bus.Event) {
 	var details traderepublic.TimelineDetailsJson
 
+	tracker := h.eventBus.Tracker()
+
 	// Unmarshal the JSON data from the event into a TimelineDetailsJson struct
 	err := details.UnmarshalJSON(event.Data.([]byte))
 	if err != nil {
-		slog.Error("failed to unmarshal timeline detail", "id", event.ID, "error", err)
+		h.deadLetter(event, fmt.Errorf("failed to unmarshal timeline detail: %w", err))
 
 		return
 	}
 
 	model := Model{}
 
+	eventType, found := h.eventTypes.Get(string(details.Id))
+	if found {
+		model.EventType, _ = eventType.(string)
+	}
+
 	err = h.resolver.SetType(details, &model)
 	if err != nil {
 		if errors.Is(err, ErrIgnoredTransactionReceived) {
-			slog.Warn("ignored transaction received", "id", event.ID)
+			slog.Warn("ignored transaction received", "id", event.ID, "reason", err)
+			tracker.Count(bus.WorkTransaction, bus.OutcomeIgnored)
+			h.eventBus.Publish(bus.NewEvent(bus.TopicTransactionSkipped, event.ID, nil))
 
 			return
 		}
 
 		if errors.Is(err, ErrCancelledTransactionReceived) {
 			slog.Warn("cancelled transaction received", "id", event.ID)
+			tracker.Count(bus.WorkTransaction, bus.OutcomeCanceled)
+			h.eventBus.Publish(bus.NewEvent(bus.TopicTransactionSkipped, event.ID, nil))
 
 			return
 		}
 
-		slog.Error("failed to resolve type", "id", event.ID, "err", err)
+		h.deadLetter(event, fmt.Errorf("failed to resolve type: %w", err))
 
 		return
 	}
 
 	err = h.mapper.Map(details, &model)
 	if err != nil {
-		slog.Error("failed to map", "id", event.ID, "err", err)
+		// A partially mapped model would end up as a wrong CSV row
+		h.deadLetter(event, fmt.Errorf("failed to map: %w", err))
+
+		return
 	}
 
+	tracker.Count(bus.WorkTransaction, bus.OutcomeMapped)
+
 	h.eventBus.Publish(bus.NewEvent(bus.TopicModelReady, model.ID, model))
 }
+
+// deadLetter publishes the event that could not be processed, so it can be retried later.
+func (h *Handler) deadLetter(event bus.Event, err error) {
+	slog.Error("failed to handle timeline detail", "id", event.ID, "error", err)
+	h.eventBus.Tracker().Count(bus.WorkTransaction, bus.OutcomeFailed)
+
+	failed := bus.FailedEvent{
+		Event: event,
+		Err:   err,
+	}
+
+	// Rules depend on the event type, which is only known from the timeline transactions
+	if eventType, found := h.eventTypes.Get(event.ID); found {
+		failed.Attributes = map[string]string{AttributeEventType: eventType.(string)}
+	}
+
+	h.eventBus.Publish(bus.NewEvent(bus.TopicDeadLetter, event.ID, failed))
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
 	gocache "github.com/patrickmn/go-cache"
 )
@@ -13,15 +15,34 @@ import (
 var ErrTransactionWithoutTypeReceived = errors.New("transaction without type received")
 
 type DataMapper struct {
-	cache *gocache.Cache
+	cache   *gocache.Cache
+	locale  traderepublic.Locale     // Locale the amounts are rendered in
+	waiting map[string]chan struct{} // Closed once the instrument of the ISIN it is keyed by is cached
+	mu      sync.Mutex
 }
 
-func NewDataMapper(cache *gocache.Cache) *DataMapper {
+func NewDataMapper(cache *gocache.Cache, locale traderepublic.Locale) *DataMapper {
 	return &DataMapper{
-		cache: cache,
+		cache:   cache,
+		locale:  locale,
+		waiting: make(map[string]chan struct{}),
 	}
 }
 
+// HandleInstrumentCached wakes the transactions waiting for the instrument of a bus.TopicInstrumentCached event.
+func (m *DataMapper) HandleInstrumentCached(event bus.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cached, found := m.waiting[event.ID]
+	if !found {
+		return
+	}
+
+	close(cached)
+	delete(m.waiting, event.ID)
+}
+
 func (m *DataMapper) Map(details traderepublic.TimelineDetailsJson, model *Model) error {
 	if model.Type == nil {
 		return fmt.Errorf("%w: %s", ErrTransactionWithoutTypeReceived, details.Id)
@@ -59,24 +80,27 @@ func (m *DataMapper) Map(details traderepublic.TimelineDetailsJson, model *Model
 		return fmt.Errorf("failed to find shares in details: %w", err)
 	}
 
-	shares, err := ParseFloatFromResponse(sharesStr)
-	if err != nil {
-		return fmt.Errorf("failed to parse float from shares: %w", err)
+	if sharesStr != "" {
+		model.Shares, err = ParseLocalizedFloat(sharesStr, m.locale)
+		if err != nil {
+			return fmt.Errorf("failed to parse float from shares: %w", err)
+		}
 	}
 
-	model.Shares = shares
-
 	shaePriceStr, err := model.Type.FindSharePrice(details)
 	if err != nil {
 		return fmt.Errorf("failed to find share price in details: %w", err)
 	}
 
-	sharePrice, err := ParseFloatFromResponse(shaePriceStr)
-	if err != nil {
-		return fmt.Errorf("failed to parse float from share price: %w", err)
-	}
+	if shaePriceStr != "" {
+		model.SharePrice, err = ParseLocalizedFloat(shaePriceStr, m.locale)
+		if err != nil {
+			return fmt.Errorf("failed to parse float from share price: %w", err)
+		}
 
-	model.SharePrice = sharePrice
+		// Dividends per share are often paid in the currency of the instrument
+		model.SharePriceCurrency = ParseCurrencyFromResponse(shaePriceStr)
+	}
 
 	feeStr, err := model.Type.FindFee(details)
 	if err != nil {
@@ -86,8 +110,8 @@ func (m *DataMapper) Map(details traderepublic.TimelineDetailsJson, model *Model
 	zeroFee := float64(0)
 	model.Fee = &zeroFee
 
-	if feeStr != "Free" {
-		fee, err := ParseFloatFromResponse(feeStr)
+	if feeStr != "Free" && feeStr != "" {
+		fee, err := ParseLocalizedFloat(feeStr, m.locale)
 		if err != nil {
 			return fmt.Errorf("failed to parse float from fee: %w", err)
 		}
@@ -100,12 +124,132 @@ func (m *DataMapper) Map(details traderepublic.TimelineDetailsJson, model *Model
 		return fmt.Errorf("failed to find total data: %w", err)
 	}
 
-	total, err := ParseFloatFromResponse(totalStr)
+	if totalStr != "" {
+		total, err := ParseLocalizedFloat(totalStr, m.locale)
+		if err != nil {
+			return fmt.Errorf("failed to parse float from total: %w", err)
+		}
+
+		switch model.Type.Direction() {
+		case DirectionCredit:
+			model.Credit = total
+		case DirectionDebit:
+			model.Debit = total
+		case DirectionBenefit:
+			model.Benefit = &total
+		}
+
+		model.Currency = ParseCurrencyFromResponse(totalStr)
+	}
+
+	yieldStr, err := model.Type.FindYield(details)
+	if err != nil {
+		return fmt.Errorf("failed to find yield data: %w", err)
+	}
+
+	model.Yield, err = parseOptionalFloat(yieldStr, m.locale)
+	if err != nil {
+		return fmt.Errorf("failed to parse float from yield: %w", err)
+	}
+
+	gainStr, err := model.Type.FindGain(details)
+	if err != nil {
+		return fmt.Errorf("failed to find gain data: %w", err)
+	}
+
+	model.Gain, err = parseOptionalFloat(gainStr, m.locale)
 	if err != nil {
-		return fmt.Errorf("failed to parse float from total: %w", err)
+		return fmt.Errorf("failed to parse float from gain: %w", err)
 	}
 
-	model.Debit = total
+	taxStr, err := model.Type.FindTax(details)
+	if err != nil {
+		return fmt.Errorf("failed to find tax data: %w", err)
+	}
+
+	model.TaxAmount, err = parseOptionalFloat(taxStr, m.locale)
+	if err != nil {
+		return fmt.Errorf("failed to parse float from tax: %w", err)
+	}
+
+	grossStr, err := model.Type.FindGross(details)
+	if err != nil {
+		return fmt.Errorf("failed to find gross data: %w", err)
+	}
+
+	model.Gross, err = parseOptionalFloat(grossStr, m.locale)
+	if err != nil {
+		return fmt.Errorf("failed to parse float from gross: %w", err)
+	}
+
+	averageBalanceStr, err := model.Type.FindAverageBalance(details)
+	if err != nil {
+		return fmt.Errorf("failed to find average balance data: %w", err)
+	}
+
+	model.AverageBalance, err = parseOptionalFloat(averageBalanceStr, m.locale)
+	if err != nil {
+		return fmt.Errorf("failed to parse float from average balance: %w", err)
+	}
+
+	annualRateStr, err := model.Type.FindAnnualRate(details)
+	if err != nil {
+		return fmt.Errorf("failed to find annual rate data: %w", err)
+	}
+
+	model.AnnualRate, err = parseOptionalFloat(annualRateStr, m.locale)
+	if err != nil {
+		return fmt.Errorf("failed to parse float from annual rate: %w", err)
+	}
+
+	model.Merchant, err = model.Type.FindMerchant(details)
+	if err != nil {
+		return fmt.Errorf("failed to find merchant data: %w", err)
+	}
+
+	originalAmountStr, err := model.Type.FindOriginalAmount(details)
+	if err != nil {
+		return fmt.Errorf("failed to find original amount data: %w", err)
+	}
+
+	model.OriginalAmount, err = parseOptionalFloat(originalAmountStr, m.locale)
+	if err != nil {
+		return fmt.Errorf("failed to parse float from original amount: %w", err)
+	}
+
+	model.OriginalCurrency = ParseCurrencyFromResponse(originalAmountStr)
+
+	exchangeRateStr, err := model.Type.FindExchangeRate(details)
+	if err != nil {
+		return fmt.Errorf("failed to find exchange rate data: %w", err)
+	}
+
+	model.ExchangeRate, err = parseOptionalFloat(exchangeRateStr, m.locale)
+	if err != nil {
+		return fmt.Errorf("failed to parse float from exchange rate: %w", err)
+	}
+
+	model.Counterparty, err = model.Type.FindCounterparty(details)
+	if err != nil {
+		return fmt.Errorf("failed to find counterparty data: %w", err)
+	}
+
+	iban, err := model.Type.FindIBAN(details)
+	if err != nil {
+		return fmt.Errorf("failed to find IBAN data: %w", err)
+	}
+
+	model.IBAN = MaskIBAN(iban)
+
+	model.Reference, err = model.Type.FindReference(details)
+	if err != nil {
+		return fmt.Errorf("failed to find reference data: %w", err)
+	}
+
+	model.Sources, err = model.Type.FindSources(details)
+	if err != nil {
+		return fmt.Errorf("failed to find sources data: %w", err)
+	}
 
 	if isin != "" {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -119,109 +263,97 @@ func (m *DataMapper) Map(details traderepublic.TimelineDetailsJson, model *Model
 
 		model.AssetName = *instr.ShortName
 		model.AssetType = string(instr.TypeId)
+
+		if instr.DerivativeInfo != nil {
+			mapDerivative(instr, model)
+		}
 	}
 
 	return nil
+}
+
+// mapDerivative copies the product details of warrants, knock-out and leveraged products into the model.
+func mapDerivative(instr traderepublic.InstrumentJson, model *Model) {
+	info := instr.DerivativeInfo
+
+	if info.ProductCategoryName != nil {
+		model.ProductType = *info.ProductCategoryName
+	} else if info.CategoryType != nil {
+		model.ProductType = *info.CategoryType
+	}
 
-	// header, err := details.SectionHeader()
-	// if err != nil {
-	// 	return Model{}, fmt.Errorf("failed to find header section: %w", err)
-	// }
-
-	// overview, err := details.FindSection(traderepublic.SectionOverview)
-	// if err != nil {
-	// 	return Model{}, fmt.Errorf("failed to find overview section: %w", err)
-	// }
-
-	// model := Model{
-	// 	ID:     string(details.Id),
-	// 	Status: string(header.Data.Status),
-	// }
-
-	// timestamp, err := ParseTimestamp(header.Data.Timestamp)
-	// if err != nil {
-	// 	return model, fmt.Errorf("failed to parse timestamp: %w", err)
-	// }
-
-	// model.Timestamp = CSVDateTime{Time: timestamp}
-
-	// switch trnType {
-	// case TypeBuyOrder, TypeSellOrder, TypeRoundUp, TypeSaveback:
-	// 	model.ISIN = header.Action.Payload
-
-	// 	trn, err := details.FindSection(traderepublic.SectionTransaction)
-	// 	if err != nil {
-	// 		return model, fmt.Errorf("failed to find transaction section: %w", err)
-	// 	}
-
-	// 	shares, err := trn.FindData(traderepublic.DataShares)
-	// 	if err != nil {
-	// 		return model, fmt.Errorf("failed to find shares data: %w", err)
-	// 	}
-
-	// 	model.Shares, err = strconv.ParseFloat(shares.Detail.Text, 64)
-	// 	if err != nil {
-	// 		return model, fmt.Errorf("failed to parse float from shares: %w", err)
-	// 	}
-
-	// case TypeSavingsplan:
-	// 	model.ISIN = header.Action.Payload
-
-	// 	var sharesVal string
-
-	// 	trn, err := overview.FindData(traderepublic.DataTransaction)
-	// 	if err != nil {
-	// 		trnSection, err := details.FindSection(traderepublic.SectionTransaction)
-	// 		if err != nil {
-
-	// 		}
-	// 		if err == nil {
-	// 			shares, err := trnSection.FindData(traderepublic.DataShares)
-	// 			if err == nil {
-	// 				sharesVal = shares.Detail.Text
-
-	// 				break
-	// 			}
-	// 		}
-
-	// 		return model, fmt.Errorf("failed to find transaction data: %w", err)
-	// 	}
-
-	// 	if trn.Detail.DisplayValue != nil {
-	// 		sharesVal = *trn.Detail.DisplayValue.Prefix
-	// 	}
-
-	// 	model.Shares, err = ParseFloatFromResponse(sharesVal)
-	// 	if err != nil {
-	// 		return model, fmt.Errorf("failed to parse shares data: %w", err)
-	// 	}
-	// case TypeDividendsIncome:
-	// 	model.ISIN, err = ExtractInstrumentISINFromIcon(header.Data.Icon)
-	// 	if err != nil {
-	// 		return model, fmt.Errorf("failed to extract ISIN from icon: %w", err)
-	// 	}
-	// }
-
-	// return model, nil
+	if info.Underlying != nil && info.Underlying.Name != nil {
+		model.Underlying = *info.Underlying.Name
+	}
+
+	if instr.IssuerDisplayName != nil {
+		model.Issuer = *instr.IssuerDisplayName
+	} else if instr.Issuer != nil {
+		model.Issuer = *instr.Issuer
+	}
+
+	if info.Properties == nil {
+		return
+	}
+
+	model.Strike = info.Properties.Strike
+	model.Barrier = info.Properties.Barrier
+
+	if info.Properties.Expiry != nil {
+		model.Expiry = *info.Properties.Expiry
+	}
 }
 
-func (m *DataMapper) getInstrument(ctx context.Context, isin string) (traderepublic.InstrumentJson, error) {
+// parseOptionalFloat parses a value that is not present in every transaction, nil is returned for an empty value.
+func parseOptionalFloat(src string, locale traderepublic.Locale) (*float64, error) {
+	if src == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	value, err := ParseLocalizedFloat(src, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	return &value, nil
+}
 
+// getInstrument returns the cached instrument of the ISIN, waiting until it is cached or the context is done.
+func (m *DataMapper) getInstrument(ctx context.Context, isin string) (traderepublic.InstrumentJson, error) {
 	for {
+		// Registered before looking up the cache, so an instrument cached in between is not missed
+		cached := m.cached(isin)
+
+		entry, found := m.cache.Get(isin)
+		if found {
+			// Failed instrument subscriptions are cached as their error
+			if err, failed := entry.(error); failed {
+				return traderepublic.InstrumentJson{}, fmt.Errorf("instrument subscription failed: %w", err)
+			}
+
+			if instr, ok := entry.(traderepublic.InstrumentJson); ok {
+				return instr, nil
+			}
+		}
+
 		select {
 		case <-ctx.Done():
 			return traderepublic.InstrumentJson{}, fmt.Errorf("context timeout: %w", ctx.Err())
+		case <-cached:
+		}
+	}
+}
 
-		default:
-			entry, found := m.cache.Get(isin)
-			if found {
-				instr, ok := entry.(traderepublic.InstrumentJson)
-				if !ok {
-					continue
-				}
+// cached returns the channel closed once the instrument of the ISIN is cached.
+func (m *DataMapper) cached(isin string) <-chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-				return instr, nil
-			}
-		}
+	cached, found := m.waiting[isin]
+	if !found {
+		cached = make(chan struct{})
+		m.waiting[isin] = cached
 	}
+
+	return cached
 }
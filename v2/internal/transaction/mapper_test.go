@@ -5,7 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/bus"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/transaction"
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
 	gocache "github.com/patrickmn/go-cache"
@@ -46,8 +48,8 @@ func TestDataMapper_Map(t *testing.T) {
 		}
 	}
 
-	resolver := transaction.NewTypeResolver()
-	mapper := transaction.NewDataMapper(cache)
+	resolver := transaction.NewTypeResolver(loadRules(t), traderepublic.LocaleEN)
+	mapper := transaction.NewDataMapper(cache, traderepublic.LocaleEN)
 
 	for _, entry := range entries {
 		contents, err := os.ReadFile(filepath.Join(detailsPath, entry.Name()))
@@ -88,7 +90,421 @@ func TestDataMapper_Map(t *testing.T) {
 			assert.NotEmpty(t, model.Shares)
 			assert.NotEmpty(t, model.SharePrice)
 			assert.NotNil(t, model.Fee)
-			assert.NotEmpty(t, model.Debit)
+			assert.NotZero(t, model.Debit+model.Credit)
 		})
 	}
 }
+
+func TestDataMapper_MapFakes(t *testing.T) {
+	t.Parallel()
+
+	ptr := func(v float64) *float64 { return &v }
+
+	testCases := []struct {
+		filepath string
+		isin     string
+		expected transaction.Model
+	}{
+		{
+			filepath: "../../tests/fakes/05d28e4e-e07e-424f-b5c8-a79815865dbd.json",
+			isin:     "US6701002056",
+			expected: transaction.Model{
+				ID:                 "05d28e4e-e07e-424f-b5c8-a79815865dbd",
+				Status:             "executed",
+				ISIN:               "US6701002056",
+				Shares:             5.186721,
+				SharePrice:         96.40,
+				SharePriceCurrency: "EUR",
+				Fee:                ptr(1),
+				Debit:              501,
+				Currency:           "EUR",
+			},
+		},
+		{
+			filepath: "../../tests/fakes/deb6f4dc-893c-4f15-aa1d-edc97376952b.json",
+			isin:     "XF000XRP0018",
+			expected: transaction.Model{
+				ID:                 "deb6f4dc-893c-4f15-aa1d-edc97376952b",
+				Status:             "executed",
+				ISIN:               "XF000XRP0018",
+				Shares:             424.993643,
+				SharePrice:         0.5284,
+				SharePriceCurrency: "EUR",
+				Yield:              ptr(4.61),
+				Gain:               ptr(9.89),
+				Fee:                ptr(1),
+				Credit:             223.55,
+				Currency:           "EUR",
+			},
+		},
+		{
+			filepath: "../../tests/fakes/4b6d8f0a-2c4e-4a6c-8e0a-2c4e6a8c0e2a.json",
+			isin:     "US6701002056",
+			expected: transaction.Model{
+				ID:                 "4b6d8f0a-2c4e-4a6c-8e0a-2c4e6a8c0e2a",
+				Status:             "executed",
+				ISIN:               "US6701002056",
+				Shares:             5,
+				SharePrice:         93,
+				SharePriceCurrency: "EUR",
+				Yield:              ptr(-8.25),
+				Gain:               ptr(-42.10),
+				Fee:                ptr(1),
+				Credit:             464,
+				Currency:           "EUR",
+			},
+		},
+		{
+			filepath: "../../tests/fakes/e3a5c7e9-1b3d-4f5a-9c7e-1a3c5e7a9c1e.json",
+			isin:     "XF000SOL0012",
+			expected: transaction.Model{
+				ID:                 "e3a5c7e9-1b3d-4f5a-9c7e-1a3c5e7a9c1e",
+				Status:             "executed",
+				ISIN:               "XF000SOL0012",
+				Shares:             0.35117263,
+				SharePrice:         142.37,
+				SharePriceCurrency: "EUR",
+				Fee:                ptr(0),
+				Debit:              50,
+				Currency:           "EUR",
+			},
+		},
+		{
+			filepath: "../../tests/fakes/73fc417a-62ef-4179-a85e-9f3b29224567.json",
+			isin:     "XF000DOT0011",
+			expected: transaction.Model{
+				ID:                 "73fc417a-62ef-4179-a85e-9f3b29224567",
+				Status:             "executed",
+				ISIN:               "XF000DOT0011",
+				Shares:             2.270212,
+				SharePrice:         6.61,
+				SharePriceCurrency: "EUR",
+				Fee:                ptr(0),
+				Benefit:            ptr(15),
+				Sources:            []string{"Aldi, €9.40, 2024-02-03", "Deutsche Bahn, €5.60, 2024-02-19"},
+				Currency:           "EUR",
+			},
+		},
+		{
+			filepath: "../../tests/fakes/265cb9c0-664a-45d4-b179-3061f196dd2a.json",
+			isin:     "DE000A0F5UF5",
+			expected: transaction.Model{
+				ID:                 "265cb9c0-664a-45d4-b179-3061f196dd2a",
+				Status:             "executed",
+				ISIN:               "DE000A0F5UF5",
+				Shares:             0.006882,
+				SharePrice:         158.38,
+				SharePriceCurrency: "EUR",
+				Fee:                ptr(0),
+				Debit:              1.09,
+				Sources:            []string{},
+				Currency:           "EUR",
+			},
+		},
+		{
+			filepath: "../../tests/fakes/a0e4c36a-e0ee-4183-a725-09fb1c6b3c33.json",
+			isin:     "IE0031442068",
+			expected: transaction.Model{
+				ID:                 "a0e4c36a-e0ee-4183-a725-09fb1c6b3c33",
+				Status:             "executed",
+				ISIN:               "IE0031442068",
+				Shares:             30.447001,
+				SharePrice:         0.15,
+				SharePriceCurrency: "USD",
+				Fee:                ptr(0),
+				Credit:             4.13,
+				TaxAmount:          ptr(0),
+				Gross:              ptr(4.13),
+				Currency:           "EUR",
+			},
+		},
+		{
+			filepath: "../../tests/fakes/c2e4a6b8-0d2f-4e6a-8c0e-2a4c6e8a0c2e.json",
+			isin:     "US0378331005",
+			expected: transaction.Model{
+				ID:                 "c2e4a6b8-0d2f-4e6a-8c0e-2a4c6e8a0c2e",
+				Status:             "executed",
+				ISIN:               "US0378331005",
+				Shares:             35,
+				SharePrice:         0.25,
+				SharePriceCurrency: "USD",
+				Fee:                ptr(0),
+				Credit:             6.42,
+				TaxAmount:          ptr(-1.13),
+				Gross:              ptr(7.55),
+				Currency:           "EUR",
+			},
+		},
+		{
+			filepath: "../../tests/fakes/3b7d3c1e-64a0-4f0c-9a43-2f6f7f0e5b11.json",
+			expected: transaction.Model{
+				ID:       "3b7d3c1e-64a0-4f0c-9a43-2f6f7f0e5b11",
+				Status:   "executed",
+				Debit:    5.95,
+				Currency: "EUR",
+				Merchant: "Aldi",
+				Fee:      ptr(0),
+			},
+		},
+		{
+			filepath: "../../tests/fakes/8e2f95c4-0b7e-4c59-8d5c-1a0c6a9c2d47.json",
+			expected: transaction.Model{
+				ID:               "8e2f95c4-0b7e-4c59-8d5c-1a0c6a9c2d47",
+				Status:           "executed",
+				Debit:            6.19,
+				Currency:         "EUR",
+				Merchant:         "Lidl Praha",
+				OriginalAmount:   ptr(155),
+				OriginalCurrency: "CZK",
+				ExchangeRate:     ptr(25.0404),
+				Fee:              ptr(0),
+			},
+		},
+		{
+			filepath: "../../tests/fakes/c41a7d0b-2f55-4e3b-b1f6-6d9b0f4e7a82.json",
+			expected: transaction.Model{
+				ID:       "c41a7d0b-2f55-4e3b-b1f6-6d9b0f4e7a82",
+				Status:   "executed",
+				Credit:   19.99,
+				Currency: "EUR",
+				Merchant: "Zalando",
+				Fee:      ptr(0),
+			},
+		},
+		{
+			filepath: "../../tests/fakes/f0d6a1e9-7c3b-4a8e-9e25-4b1d8c6f3a90.json",
+			expected: transaction.Model{
+				ID:       "f0d6a1e9-7c3b-4a8e-9e25-4b1d8c6f3a90",
+				Status:   "canceled",
+				Merchant: "Spotify",
+				Fee:      ptr(0),
+			},
+		},
+		{
+			filepath: "../../tests/fakes/5a9e2c71-d3f8-4b06-a1e4-97c3b5d2f068.json",
+			expected: transaction.Model{
+				ID:       "5a9e2c71-d3f8-4b06-a1e4-97c3b5d2f068",
+				Status:   "executed",
+				Merchant: "Apple",
+				Fee:      ptr(0),
+			},
+		},
+		{
+			filepath: "../../tests/fakes/2d4f6b8a-1c3e-4a5b-9d7f-0e2c4a6b8d1f.json",
+			expected: transaction.Model{
+				ID:           "2d4f6b8a-1c3e-4a5b-9d7f-0e2c4a6b8d1f",
+				Credit:       1000,
+				Counterparty: "Max Mustermann",
+				IBAN:         "DE89 •••• 3000",
+				Reference:    "Monthly top-up",
+				Status:       "executed",
+				Fee:          ptr(0),
+				Currency:     "EUR",
+			},
+		},
+		{
+			filepath: "../../tests/fakes/7e9a1b3c-5d7f-4e2a-8b4c-6d8e0f2a4b6c.json",
+			expected: transaction.Model{
+				ID:           "7e9a1b3c-5d7f-4e2a-8b4c-6d8e0f2a4b6c",
+				Debit:        250,
+				Counterparty: "Max Mustermann",
+				IBAN:         "DE89 •••• 3000",
+				Reference:    "Rent",
+				Status:       "executed",
+				Fee:          ptr(0),
+				Currency:     "EUR",
+			},
+		},
+		{
+			filepath: "../../tests/fakes/b5c7d9e1-3f5a-4b7c-9e1a-3c5e7a9b1d3f.json",
+			expected: transaction.Model{
+				ID:       "b5c7d9e1-3f5a-4b7c-9e1a-3c5e7a9b1d3f",
+				Credit:   50,
+				Status:   "executed",
+				Fee:      ptr(0),
+				Currency: "EUR",
+			},
+		},
+		{
+			filepath: "../../tests/fakes/6c8e0a2b-4d6f-4a8c-b0d2-e4f6a8c0b2d4.json",
+			expected: transaction.Model{
+				ID:             "6c8e0a2b-4d6f-4a8c-b0d2-e4f6a8c0b2d4",
+				Credit:         1.31,
+				AverageBalance: ptr(712.40),
+				AnnualRate:     ptr(2.25),
+				Status:         "executed",
+				Fee:            ptr(0),
+				Currency:       "EUR",
+			},
+		},
+		{
+			filepath: "../../tests/fakes/9f1b3d5e-7a9c-4b1d-8f3a-5c7e9b1d3f5a.json",
+			expected: transaction.Model{
+				ID:        "9f1b3d5e-7a9c-4b1d-8f3a-5c7e9b1d3f5a",
+				Credit:    0.98,
+				Gross:     ptr(1.33),
+				TaxAmount: ptr(-0.35),
+				Status:    "executed",
+				Fee:       ptr(0),
+				Currency:  "EUR",
+			},
+		},
+	}
+
+	cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
+	resolver := transaction.NewTypeResolver(loadRules(t), traderepublic.LocaleEN)
+	mapper := transaction.NewDataMapper(cache, traderepublic.LocaleEN)
+
+	for _, testCase := range testCases {
+		var name, assetType string
+
+		// Cash transactions are not linked to an instrument
+		if testCase.isin != "" {
+			name = "Asset " + testCase.isin
+			assetType = string(traderepublic.InstrumentJsonTypeIdStock)
+
+			cache.Set(testCase.isin, traderepublic.InstrumentJson{
+				Isin:      testCase.isin,
+				ShortName: &name,
+				TypeId:    traderepublic.InstrumentJsonTypeIdStock,
+			}, gocache.NoExpiration)
+		}
+
+		t.Run("it maps "+filepath.Base(testCase.filepath), func(t *testing.T) {
+			t.Parallel()
+
+			contents, err := os.ReadFile(testCase.filepath)
+			require.NoError(t, err)
+
+			var details traderepublic.TimelineDetailsJson
+
+			err = details.UnmarshalJSON(contents)
+			require.NoError(t, err)
+
+			model := transaction.Model{}
+
+			err = resolver.SetType(details, &model)
+			require.NoError(t, err)
+
+			err = mapper.Map(details, &model)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected.ID, model.ID)
+			assert.Equal(t, testCase.expected.Status, model.Status)
+			assert.Equal(t, testCase.expected.ISIN, model.ISIN)
+			assert.Equal(t, name, model.AssetName)
+			assert.Equal(t, assetType, model.AssetType)
+			assert.InDelta(t, testCase.expected.Shares, model.Shares, 0.0000001)
+			assert.InDelta(t, testCase.expected.SharePrice, model.SharePrice, 0.0000001)
+			assert.Equal(t, testCase.expected.SharePriceCurrency, model.SharePriceCurrency)
+			assert.Equal(t, testCase.expected.Yield, model.Yield)
+			assert.Equal(t, testCase.expected.Gain, model.Gain)
+			assert.Equal(t, testCase.expected.Fee, model.Fee)
+			assert.InDelta(t, testCase.expected.Debit, model.Debit, 0.0000001)
+			assert.InDelta(t, testCase.expected.Credit, model.Credit, 0.0000001)
+			assert.Equal(t, testCase.expected.Currency, model.Currency)
+			assert.Equal(t, testCase.expected.TaxAmount, model.TaxAmount)
+			assert.Equal(t, testCase.expected.Gross, model.Gross)
+			assert.Equal(t, testCase.expected.Benefit, model.Benefit)
+			assert.Equal(t, testCase.expected.Sources, model.Sources)
+			assert.Equal(t, testCase.expected.Merchant, model.Merchant)
+			assert.Equal(t, testCase.expected.OriginalAmount, model.OriginalAmount)
+			assert.Equal(t, testCase.expected.OriginalCurrency, model.OriginalCurrency)
+			assert.Equal(t, testCase.expected.ExchangeRate, model.ExchangeRate)
+			assert.Equal(t, testCase.expected.Counterparty, model.Counterparty)
+			assert.Equal(t, testCase.expected.IBAN, model.IBAN)
+			assert.Equal(t, testCase.expected.Reference, model.Reference)
+			assert.Equal(t, testCase.expected.AverageBalance, model.AverageBalance)
+			assert.Equal(t, testCase.expected.AnnualRate, model.AnnualRate)
+		})
+	}
+}
+
+func TestDataMapper_MapDerivative(t *testing.T) {
+	t.Parallel()
+
+	contents, err := os.ReadFile("../../tests/fakes/instruments/DE000SU1AB23.json")
+	require.NoError(t, err)
+
+	var instr traderepublic.InstrumentJson
+
+	err = instr.UnmarshalJSON(contents)
+	require.NoError(t, err)
+
+	cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
+	cache.Set(instr.Isin, instr, gocache.NoExpiration)
+
+	contents, err = os.ReadFile("../../tests/fakes/1a3c5e7a-9b1d-4f3a-8c5e-7a9b1d3f5a7c.json")
+	require.NoError(t, err)
+
+	var details traderepublic.TimelineDetailsJson
+
+	err = details.UnmarshalJSON(contents)
+	require.NoError(t, err)
+
+	model := transaction.Model{}
+
+	err = transaction.NewTypeResolver(loadRules(t), traderepublic.LocaleEN).SetType(details, &model)
+	require.NoError(t, err)
+
+	err = transaction.NewDataMapper(cache, traderepublic.LocaleEN).Map(details, &model)
+	require.NoError(t, err)
+
+	strike := 120.0
+
+	assert.Equal(t, "DE000SU1AB23", model.ISIN)
+	assert.Equal(t, string(traderepublic.InstrumentJsonTypeIdDerivative), model.AssetType)
+	assert.Equal(t, "NVIDIA Turbo Long 120", model.AssetName)
+	assert.Equal(t, "Turbo Long", model.ProductType)
+	assert.Equal(t, "NVIDIA", model.Underlying)
+	assert.Equal(t, "Société Générale", model.Issuer)
+	assert.Equal(t, &strike, model.Strike)
+	assert.Equal(t, &strike, model.Barrier)
+	assert.Equal(t, "2025-06-20", model.Expiry)
+	assert.InDelta(t, 125, model.Shares, 0.0000001)
+	assert.InDelta(t, 301, model.Debit, 0.0000001)
+}
+
+func TestDataMapper_Map_WaitsForInstrument(t *testing.T) {
+	t.Parallel()
+
+	const isin = "US6701002056"
+
+	contents, err := os.ReadFile("../../tests/fakes/05d28e4e-e07e-424f-b5c8-a79815865dbd.json")
+	require.NoError(t, err)
+
+	var details traderepublic.TimelineDetailsJson
+
+	require.NoError(t, details.UnmarshalJSON(contents))
+
+	cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
+	resolver := transaction.NewTypeResolver(loadRules(t), traderepublic.LocaleEN)
+	mapper := transaction.NewDataMapper(cache, traderepublic.LocaleEN)
+
+	model := transaction.Model{}
+	require.NoError(t, resolver.SetType(details, &model))
+
+	mapped := make(chan error, 1)
+
+	go func() {
+		mapped <- mapper.Map(details, &model)
+	}()
+
+	select {
+	case err := <-mapped:
+		require.FailNow(t, "mapped before the instrument was cached", "error", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	name := "NVIDIA"
+	cache.Set(isin, traderepublic.InstrumentJson{Isin: isin, ShortName: &name}, gocache.NoExpiration)
+	mapper.HandleInstrumentCached(bus.NewEvent(bus.TopicInstrumentCached, isin, nil))
+
+	select {
+	case err := <-mapped:
+		require.NoError(t, err)
+		assert.Equal(t, name, model.AssetName)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "not mapped after the instrument was cached")
+	}
+}
@@ -1,23 +1,44 @@
 package transaction
 
 type Model struct {
-	ID             string
-	Status         string
-	Timestamp      CSVDateTime
-	Type           Type
-	AssetType      string
-	AssetName      string
-	ISIN           string
-	Shares         float64
-	SharePrice     float64
-	Yield          *float64
-	Gain           *float64
-	Fee            *float64
-	Debit          float64
-	Credit         float64
-	TaxAmount      *float64
-	InvestedAmount *float64 `csv:"-"`
-	Documents      []string
+	ID                 string
+	Status             string
+	Timestamp          CSVDateTime
+	Type               Type
+	AssetType          string
+	AssetName          string
+	ISIN               string
+	ProductType        string
+	Underlying         string
+	Issuer             string
+	Strike             *float64
+	Barrier            *float64
+	Expiry             string
+	Shares             float64
+	SharePrice         float64
+	SharePriceCurrency string
+	Yield              *float64
+	Gain               *float64
+	Fee                *float64
+	Debit              float64
+	Credit             float64
+	TaxAmount          *float64
+	Gross              *float64
+	AverageBalance     *float64
+	AnnualRate         *float64
+	Benefit            *float64
+	Currency           string
+	Merchant           string
+	OriginalAmount     *float64
+	OriginalCurrency   string
+	ExchangeRate       *float64
+	Counterparty       string
+	IBAN               string
+	Reference          string
+	Sources            []string
+	InvestedAmount     *float64 `csv:"-"`
+	EventType          string   `csv:"-"`
+	Documents          []string
 }
 
 func NewModelBuilder() *ModelBuilder {
@@ -63,13 +63,13 @@ func TestModelBuilder(t *testing.T) {
 		assert.Equal(t, expectedInstrument, model.ISIN)
 		assert.Equal(t, expectedShares, model.Shares)
 		assert.Equal(t, expectedRate, model.SharePrice)
-		assert.Equal(t, expectedYield, model.Yield)
-		assert.Equal(t, expectedProfit, model.Gain)
-		assert.Equal(t, expectedCommission, model.Fee)
+		assert.Equal(t, expectedYield, *model.Yield)
+		assert.Equal(t, expectedProfit, *model.Gain)
+		assert.Equal(t, expectedCommission, *model.Fee)
 		assert.Equal(t, expectedDebit, model.Debit)
 		assert.Equal(t, expectedCredit, model.Credit)
-		assert.Equal(t, expectedTaxAmount, model.TaxAmount)
-		assert.Equal(t, expectedInvestedAmount, model.InvestedAmount)
+		assert.Equal(t, expectedTaxAmount, *model.TaxAmount)
+		assert.Equal(t, expectedInvestedAmount, *model.InvestedAmount)
 		assert.Equal(t, expectedDocuments, model.Documents)
 	})
 }
@@ -0,0 +1,504 @@
+package transaction
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule kinds that do not resolve into a type but skip the transaction.
+const (
+	RuleKindIgnored  = "ignored"
+	RuleKindCanceled = "canceled"
+)
+
+// HeaderSectionPath is the section name under which field paths read from the header section.
+const HeaderSectionPath = "header"
+
+var ErrInvalidRules = errors.New("invalid transaction rules")
+
+//go:embed rules.yaml
+var defaultRules []byte
+
+// ruleKinds maps the type names used in the rules to the types reading the transaction values.
+var ruleKinds = map[string]func() Type{
+	"savings_plan":            func() Type { return &SavingsPlanType{} },
+	"savings_plan_pre_202502": func() Type { return &SavingsPlanPre202502Type{} },
+	"buy_order":               func() Type { return &BuyOrderType{} },
+	"sell_order":              func() Type { return &SellOrderType{} },
+	"crypto_buy":              func() Type { return &CryptoBuyType{} },
+	"crypto_sell":             func() Type { return &CryptoSellType{} },
+	"saveback":                func() Type { return &SavebackType{} },
+	"round_up":                func() Type { return &RoundUpType{} },
+	"dividend":                func() Type { return &DividendType{} },
+	"card_payment":            func() Type { return &CardPaymentType{} },
+	"card_refund":             func() Type { return &CardRefundType{} },
+	"card_failed":             func() Type { return &CardFailedType{} },
+	"card_verification":       func() Type { return &CardVerificationType{} },
+	"deposit":                 func() Type { return &DepositType{} },
+	"direct_debit":            func() Type { return &DirectDebitType{} },
+	"withdrawal":              func() Type { return &WithdrawalType{} },
+	"interest_payment":        func() Type { return &InterestPaymentType{} },
+}
+
+var ruleDirections = map[string]Direction{
+	"debit":   DirectionDebit,
+	"credit":  DirectionCredit,
+	"benefit": DirectionBenefit,
+}
+
+var ruleFields = []string{
+	"isin", "shares", "sharePrice", "fee", "total", "yield", "gain", "tax", "gross", "averageBalance",
+	"annualRate", "merchant", "originalAmount", "exchangeRate", "counterparty", "iban", "reference",
+}
+
+// RuleSet is the ordered list of rules resolving transaction types together with the title aliases
+// they rely on and the locations of the values of each type.
+type RuleSet struct {
+	Aliases map[string][]string             `yaml:"aliases"`
+	Fields  map[string]map[string]FieldPath `yaml:"fields"`
+	Rules   []Rule                          `yaml:"rules"`
+}
+
+// Rule describes a transaction type: the conditions its details have to meet and, optionally,
+// where its values are located when they differ from the locations of the type.
+type Rule struct {
+	Name       string               `yaml:"name"`
+	Type       string               `yaml:"type"`
+	Label      string               `yaml:"label"`
+	Direction  string               `yaml:"direction"`
+	EventTypes []string             `yaml:"eventTypes"`
+	When       []Condition          `yaml:"when"`
+	Fields     map[string]FieldPath `yaml:"fields"`
+}
+
+// Condition holds when all of its non-empty keys hold.
+type Condition struct {
+	Status     string      `yaml:"status"`
+	Section    string      `yaml:"section"`
+	Data       string      `yaml:"data"`
+	Text       []string    `yaml:"text"`
+	Step       string      `yaml:"step"`
+	Document   string      `yaml:"document"`
+	ISINPrefix string      `yaml:"isinPrefix"`
+	AnyOf      []Condition `yaml:"anyOf"`
+}
+
+// FieldPath locates a value in the transaction details.
+type FieldPath struct {
+	Section  string      `yaml:"section"`
+	Data     string      `yaml:"data"`
+	Value    string      `yaml:"value"`
+	Crypto   bool        `yaml:"crypto"`
+	Signed   bool        `yaml:"signed"`
+	Negated  bool        `yaml:"negated"`
+	Optional bool        `yaml:"optional"`
+	AnyOf    []FieldPath `yaml:"anyOf"`
+}
+
+// LoadRules loads the embedded rules and merges the rules file at overridePath on top of them,
+// the override file is skipped when the path is empty.
+func LoadRules(overridePath string) (*RuleSet, error) {
+	rules, err := parseRules(defaultRules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse default rules: %w", err)
+	}
+
+	if overridePath == "" {
+		return rules, nil
+	}
+
+	contents, err := os.ReadFile(overridePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	override, err := parseRules(contents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rules file %s: %w", overridePath, err)
+	}
+
+	rules.merge(override)
+
+	return rules, nil
+}
+
+func parseRules(contents []byte) (*RuleSet, error) {
+	var rules RuleSet
+
+	err := yaml.Unmarshal(contents, &rules)
+	if err != nil {
+		return nil, err
+	}
+
+	for kind, fields := range rules.Fields {
+		_, ok := ruleKinds[kind]
+		if !ok {
+			return nil, fmt.Errorf("%w: fields of unknown type %s", ErrInvalidRules, kind)
+		}
+
+		err = validateFields("type "+kind, fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, rule := range rules.Rules {
+		err = rule.validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &rules, nil
+}
+
+// merge replaces the rules having the same name in place and evaluates the other ones first, field
+// locations replace the ones of the same type and field.
+func (s *RuleSet) merge(override *RuleSet) {
+	var prepended []Rule
+
+	for _, rule := range override.Rules {
+		i := slices.IndexFunc(s.Rules, func(r Rule) bool { return r.Name == rule.Name })
+		if i < 0 {
+			prepended = append(prepended, rule)
+
+			continue
+		}
+
+		s.Rules[i] = rule
+	}
+
+	s.Rules = append(prepended, s.Rules...)
+
+	if s.Aliases == nil {
+		s.Aliases = map[string][]string{}
+	}
+
+	for title, alternatives := range override.Aliases {
+		s.Aliases[title] = append(s.Aliases[title], alternatives...)
+	}
+
+	if s.Fields == nil {
+		s.Fields = map[string]map[string]FieldPath{}
+	}
+
+	for kind, fields := range override.Fields {
+		if s.Fields[kind] == nil {
+			s.Fields[kind] = map[string]FieldPath{}
+		}
+
+		maps.Copy(s.Fields[kind], fields)
+	}
+}
+
+func (r Rule) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("%w: rule without name", ErrInvalidRules)
+	}
+
+	if len(r.When) == 0 && len(r.EventTypes) == 0 {
+		return fmt.Errorf("%w: rule %s has no conditions", ErrInvalidRules, r.Name)
+	}
+
+	_, ok := ruleKinds[r.Type]
+	if !ok && r.Type != RuleKindIgnored && r.Type != RuleKindCanceled {
+		return fmt.Errorf("%w: rule %s has unknown type %s", ErrInvalidRules, r.Name, r.Type)
+	}
+
+	_, ok = ruleDirections[r.Direction]
+	if r.Direction != "" && !ok {
+		return fmt.Errorf("%w: rule %s has unknown direction %s", ErrInvalidRules, r.Name, r.Direction)
+	}
+
+	return validateFields("rule "+r.Name, r.Fields)
+}
+
+func validateFields(owner string, fields map[string]FieldPath) error {
+	for field, path := range fields {
+		if !slices.Contains(ruleFields, field) {
+			return fmt.Errorf("%w: %s has unknown field %s", ErrInvalidRules, owner, field)
+		}
+
+		if !path.complete() {
+			return fmt.Errorf("%w: %s has incomplete path for field %s", ErrInvalidRules, owner, field)
+		}
+	}
+
+	return nil
+}
+
+// matches reports whether the transaction details meet the rule, event types are only checked when
+// the event type of the transaction is known.
+func (r Rule) matches(details traderepublic.TimelineDetailsJson, eventType string, titles traderepublic.Titles) bool {
+	if len(r.EventTypes) > 0 {
+		if eventType != "" && !slices.Contains(r.EventTypes, eventType) {
+			return false
+		}
+
+		if eventType == "" && len(r.When) == 0 {
+			return false
+		}
+	}
+
+	for _, condition := range r.When {
+		if !condition.matches(details, titles) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newType creates the type described by the rule reading the values under the titles at the locations of
+// the type overridden by the ones of the rule, types are only wrapped when the rule relabels them.
+func (r Rule) newType(titles traderepublic.Titles, typeFields map[string]FieldPath) Type {
+	base := ruleKinds[r.Type]()
+
+	fields := make(map[string]FieldPath, len(typeFields)+len(r.Fields))
+	maps.Copy(fields, typeFields)
+	maps.Copy(fields, r.Fields)
+
+	if configurable, ok := base.(interface {
+		configure(traderepublic.Titles, map[string]FieldPath)
+	}); ok {
+		configurable.configure(titles, fields)
+	}
+
+	if r.Label == "" && r.Direction == "" {
+		return base
+	}
+
+	return &RuleType{Type: base, rule: r}
+}
+
+func (c Condition) matches(details traderepublic.TimelineDetailsJson, titles traderepublic.Titles) bool {
+	if c.Status != "" || c.ISINPrefix != "" {
+		header, err := details.SectionHeader()
+		if err != nil {
+			return false
+		}
+
+		if c.Status != "" && string(header.Data.Status) != c.Status {
+			return false
+		}
+
+		if c.ISINPrefix != "" && (header.Action == nil || !strings.HasPrefix(header.Action.Payload, c.ISINPrefix)) {
+			return false
+		}
+	}
+
+	if c.Section != "" {
+		section, err := details.FindSection(titles.Expand([]string{c.Section}))
+		if err != nil {
+			return false
+		}
+
+		if c.Data != "" {
+			row, err := section.FindData(titles.Expand([]string{c.Data}))
+			if err != nil {
+				return false
+			}
+
+			if len(c.Text) > 0 && !slices.Contains(titles.Expand(c.Text), row.Detail.Text) {
+				return false
+			}
+		}
+	}
+
+	if c.Step != "" {
+		steps, err := details.SectionSteps()
+		if err != nil {
+			return false
+		}
+
+		_, err = steps.FindStep(titles.Expand([]string{c.Step}))
+		if err != nil {
+			return false
+		}
+	}
+
+	if c.Document != "" && !details.HasDocument(traderepublic.DocumentRowPostboxType(c.Document)) {
+		return false
+	}
+
+	if len(c.AnyOf) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(c.AnyOf, func(alternative Condition) bool {
+		return alternative.matches(details, titles)
+	})
+}
+
+// complete reports whether the path, or each of its alternatives, names a row or a header value.
+func (p FieldPath) complete() bool {
+	if len(p.AnyOf) > 0 {
+		return !slices.ContainsFunc(p.AnyOf, func(alternative FieldPath) bool { return !alternative.complete() })
+	}
+
+	return p.Section != "" && (p.Data != "" || p.Section == HeaderSectionPath)
+}
+
+// find reads the value located by the path under the titles.
+func (p FieldPath) find(details traderepublic.TimelineDetailsJson, titles traderepublic.Titles) (string, error) {
+	if len(p.AnyOf) > 0 {
+		return p.findAny(details, titles)
+	}
+
+	if p.Section == HeaderSectionPath {
+		return p.findHeader(details)
+	}
+
+	section, err := details.FindSection(titles.Expand([]string{p.Section}))
+	if err != nil {
+		if p.Optional {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("failed to find section: %w", err)
+	}
+
+	if p.Crypto {
+		return p.findCrypto(section, titles)
+	}
+
+	row, err := section.FindData(titles.Expand([]string{p.Data}))
+	if err != nil {
+		if p.Optional {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("failed to find data: %w", err)
+	}
+
+	value := row.Detail.Text
+
+	switch p.Value {
+	case "", "text":
+	case "prefix":
+		value = ""
+		if row.Detail.DisplayValue != nil && row.Detail.DisplayValue.Prefix != nil {
+			value = *row.Detail.DisplayValue.Prefix
+		}
+	case "displayValue":
+		value = ""
+		if row.Detail.DisplayValue != nil {
+			value = row.Detail.DisplayValue.Text
+		}
+	default:
+		return "", fmt.Errorf("%w: unknown value %s", ErrInvalidRules, p.Value)
+	}
+
+	if ((p.Signed && row.IsNegative()) || p.Negated) && !isZero(value, titles.Locale()) {
+		value = "-" + value
+	}
+
+	return value, nil
+}
+
+// isZero reports whether the value is an amount of zero, which is not negated so it never reads "-0".
+func isZero(value string, locale traderepublic.Locale) bool {
+	amount, err := ParseLocalizedFloat(value, locale)
+
+	return err == nil && amount == 0
+}
+
+// findAny returns the first non-empty value of the alternatives, alternatives that are not found are skipped.
+func (p FieldPath) findAny(details traderepublic.TimelineDetailsJson, titles traderepublic.Titles) (string, error) {
+	var errs []error
+
+	for _, alternative := range p.AnyOf {
+		value, err := alternative.find(details, titles)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		if value != "" {
+			return value, nil
+		}
+	}
+
+	if p.Optional || len(errs) < len(p.AnyOf) {
+		return "", nil
+	}
+
+	return "", errors.Join(errs...)
+}
+
+// findCrypto reads the text of a crypto row, their quantities are fractional with a varying number of decimals.
+func (p FieldPath) findCrypto(section traderepublic.TableSection, titles traderepublic.Titles) (string, error) {
+	row, err := section.FindCryptoData(titles.Expand([]string{p.Data}))
+	if err != nil {
+		if p.Optional {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("failed to find crypto data: %w", err)
+	}
+
+	if row.Detail.Text == nil {
+		return "", nil
+	}
+
+	return *row.Detail.Text, nil
+}
+
+func (p FieldPath) findHeader(details traderepublic.TimelineDetailsJson) (string, error) {
+	header, err := details.SectionHeader()
+	if err != nil {
+		return "", fmt.Errorf("failed to find header section: %w", err)
+	}
+
+	switch p.Value {
+	case "", "title":
+		return header.Title, nil
+	case "icon":
+		return header.Data.Icon, nil
+	case "action":
+		if header.Action == nil {
+			if p.Optional {
+				return "", nil
+			}
+
+			return "", fmt.Errorf("failed to find header action: %w", traderepublic.ErrDataItemNotFound)
+		}
+
+		return header.Action.Payload, nil
+	}
+
+	return "", fmt.Errorf("%w: unknown header value %s", ErrInvalidRules, p.Value)
+}
+
+// RuleType is a type relabeled by a rule, the values are read by the wrapped type.
+type RuleType struct {
+	Type
+
+	rule Rule
+}
+
+func (t *RuleType) String() string {
+	if t.rule.Label != "" {
+		return t.rule.Label
+	}
+
+	return t.Type.String()
+}
+
+func (t *RuleType) Direction() Direction {
+	direction, ok := ruleDirections[t.rule.Direction]
+	if ok {
+		return direction
+	}
+
+	return t.Type.Direction()
+}
@@ -0,0 +1,309 @@
+package transaction_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal/transaction"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadRules(t *testing.T) *transaction.RuleSet {
+	t.Helper()
+
+	rules, err := transaction.LoadRules("")
+	require.NoError(t, err)
+
+	return rules
+}
+
+func writeRules(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+
+	err := os.WriteFile(path, []byte(contents), 0o600)
+	require.NoError(t, err)
+
+	return path
+}
+
+func readDetails(t *testing.T, path string) traderepublic.TimelineDetailsJson {
+	t.Helper()
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var details traderepublic.TimelineDetailsJson
+
+	err = details.UnmarshalJSON(contents)
+	require.NoError(t, err)
+
+	return details
+}
+
+func TestLoadRules(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		contents string
+	}{
+		{
+			name: "unknown type",
+			contents: `
+rules:
+  - name: stock split
+    type: split
+    when:
+      - section: Overview
+        data: Split
+`,
+		},
+		{
+			name: "unknown direction",
+			contents: `
+rules:
+  - name: card payment
+    type: card_payment
+    direction: sideways
+    when:
+      - section: Overview
+        data: Card payment
+`,
+		},
+		{
+			name: "unknown field",
+			contents: `
+rules:
+  - name: card payment
+    type: card_payment
+    fields:
+      colour:
+        section: Overview
+        data: Colour
+    when:
+      - section: Overview
+        data: Card payment
+`,
+		},
+		{
+			name: "incomplete alternative path",
+			contents: `
+rules:
+  - name: card payment
+    type: card_payment
+    fields:
+      merchant:
+        anyOf:
+          - section: Overview
+    when:
+      - section: Overview
+        data: Card payment
+`,
+		},
+		{
+			name: "fields of unknown type",
+			contents: `
+fields:
+  split:
+    total:
+      section: Overview
+      data: Total
+`,
+		},
+		{
+			name: "no conditions",
+			contents: `
+rules:
+  - name: card payment
+    type: card_payment
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run("it rejects rule with "+testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := transaction.LoadRules(writeRules(t, testCase.contents))
+			assert.ErrorIs(t, err, transaction.ErrInvalidRules)
+		})
+	}
+
+	t.Run("it fails on missing rules file", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := transaction.LoadRules(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.Error(t, err)
+	})
+}
+
+func TestTypeResolver_SetTypeWithOverride(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it replaces rule with the same name", func(t *testing.T) {
+		t.Parallel()
+
+		rules, err := transaction.LoadRules(writeRules(t, `
+rules:
+  - name: card payment
+    type: card_payment
+    label: Card purchase
+    fields:
+      merchant:
+        section: header
+        value: icon
+      total:
+        section: Overview
+        data: Total
+        signed: true
+    when:
+      - section: Overview
+        data: Card payment
+`))
+		require.NoError(t, err)
+
+		details := readDetails(t, "../../tests/fakes/3b7d3c1e-64a0-4f0c-9a43-2f6f7f0e5b11.json")
+		model := transaction.Model{}
+
+		err = transaction.NewTypeResolver(rules, traderepublic.LocaleEN).SetType(details, &model)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Card purchase", model.Type.String())
+		assert.Equal(t, transaction.DirectionDebit, model.Type.Direction())
+
+		merchant, err := model.Type.FindMerchant(details)
+		require.NoError(t, err)
+		assert.Equal(t, "logos/merchant-a8c0c5a4-ad0e-4fc2-8fdc-6e5e7d2ac1f3/v2", merchant)
+
+		total, err := model.Type.FindTotal(details)
+		require.NoError(t, err)
+		assert.Equal(t, "€5.95", total)
+	})
+
+	t.Run("it replaces field locations of the type", func(t *testing.T) {
+		t.Parallel()
+
+		rules, err := transaction.LoadRules(writeRules(t, `
+fields:
+  deposit:
+    counterparty:
+      section: Sender
+      data: IBAN
+`))
+		require.NoError(t, err)
+
+		details := readDetails(t, "../../tests/fakes/2d4f6b8a-1c3e-4a5b-9d7f-0e2c4a6b8d1f.json")
+		model := transaction.Model{}
+
+		err = transaction.NewTypeResolver(rules, traderepublic.LocaleEN).SetType(details, &model)
+		require.NoError(t, err)
+
+		counterparty, err := model.Type.FindCounterparty(details)
+		require.NoError(t, err)
+		assert.Equal(t, "DE89370400440532013000", counterparty)
+
+		reference, err := model.Type.FindReference(details)
+		require.NoError(t, err)
+		assert.Equal(t, "Monthly top-up", reference)
+	})
+
+	t.Run("it resolves renamed sections through aliases", func(t *testing.T) {
+		t.Parallel()
+
+		rules, err := transaction.LoadRules(writeRules(t, `
+aliases:
+  Overview: [Summary]
+`))
+		require.NoError(t, err)
+
+		details := traderepublic.TimelineDetailsJson{
+			Id: "renamed-overview",
+			Sections: []any{
+				map[string]any{
+					"title": "Summary",
+					"type":  "table",
+					"data": []any{
+						map[string]any{
+							"title": "Card payment",
+							"style": "plain",
+							"detail": map[string]any{
+								"text": "•••• 4821",
+								"type": "iconWithText",
+							},
+						},
+					},
+				},
+			},
+		}
+
+		model := transaction.Model{}
+
+		err = transaction.NewTypeResolver(rules, traderepublic.LocaleEN).SetType(details, &model)
+		require.NoError(t, err)
+
+		assert.IsType(t, &transaction.CardPaymentType{}, model.Type)
+	})
+
+	t.Run("it evaluates new rules first", func(t *testing.T) {
+		t.Parallel()
+
+		rules, err := transaction.LoadRules(writeRules(t, `
+rules:
+  - name: groceries
+    type: ignored
+    when:
+      - section: Overview
+        data: Merchant
+        text: [Aldi]
+`))
+		require.NoError(t, err)
+
+		details := readDetails(t, "../../tests/fakes/3b7d3c1e-64a0-4f0c-9a43-2f6f7f0e5b11.json")
+		model := transaction.Model{}
+
+		err = transaction.NewTypeResolver(rules, traderepublic.LocaleEN).SetType(details, &model)
+		require.ErrorIs(t, err, transaction.ErrIgnoredTransactionReceived)
+		assert.Nil(t, model.Type)
+	})
+}
+
+func TestTypeResolver_SetTypeWithEventType(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		eventType string
+		expected  transaction.Type
+		err       error
+	}{
+		{eventType: "", expected: &transaction.CardPaymentType{}},
+		{eventType: "card_successful_transaction", expected: &transaction.CardPaymentType{}},
+		{eventType: transaction.EventTypeLegacyMigrated, expected: &transaction.CardPaymentType{}},
+		{eventType: "ORDER_EXECUTED", err: transaction.ErrUnknownTransactionReceived},
+	}
+
+	resolver := transaction.NewTypeResolver(loadRules(t), traderepublic.LocaleEN)
+
+	for _, testCase := range testCases {
+		t.Run("it resolves type with event type "+testCase.eventType, func(t *testing.T) {
+			t.Parallel()
+
+			details := readDetails(t, "../../tests/fakes/3b7d3c1e-64a0-4f0c-9a43-2f6f7f0e5b11.json")
+			model := transaction.Model{EventType: testCase.eventType}
+
+			err := resolver.SetType(details, &model)
+			if testCase.err != nil {
+				require.ErrorIs(t, err, testCase.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.IsType(t, testCase.expected, model.Type)
+		})
+	}
+}
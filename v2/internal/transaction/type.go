@@ -3,6 +3,10 @@ package transaction
 import (
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
 )
@@ -13,6 +17,18 @@ var (
 	ErrUnknownTransactionReceived   = errors.New("unknown transaction type received")
 )
 
+// Direction tells whether the total of a transaction is debited from or credited to the cash account.
+type Direction int
+
+const (
+	DirectionDebit Direction = iota
+	DirectionCredit
+	// DirectionBenefit is used when the total is funded by the benefits program and does not touch the cash account.
+	DirectionBenefit
+)
+
+// Type knows where the values of a transaction are located in its details.
+// Optional values are returned as an empty string when the transaction does not have them.
 type Type interface {
 	fmt.Stringer
 	FindID(details traderepublic.TimelineDetailsJson) string
@@ -23,9 +39,32 @@ type Type interface {
 	FindSharePrice(details traderepublic.TimelineDetailsJson) (string, error)
 	FindFee(details traderepublic.TimelineDetailsJson) (string, error)
 	FindTotal(details traderepublic.TimelineDetailsJson) (string, error)
+	FindYield(details traderepublic.TimelineDetailsJson) (string, error)
+	FindGain(details traderepublic.TimelineDetailsJson) (string, error)
+	FindTax(details traderepublic.TimelineDetailsJson) (string, error)
+	FindGross(details traderepublic.TimelineDetailsJson) (string, error)
+	FindAverageBalance(details traderepublic.TimelineDetailsJson) (string, error)
+	FindAnnualRate(details traderepublic.TimelineDetailsJson) (string, error)
+	FindMerchant(details traderepublic.TimelineDetailsJson) (string, error)
+	FindOriginalAmount(details traderepublic.TimelineDetailsJson) (string, error)
+	FindExchangeRate(details traderepublic.TimelineDetailsJson) (string, error)
+	FindCounterparty(details traderepublic.TimelineDetailsJson) (string, error)
+	FindIBAN(details traderepublic.TimelineDetailsJson) (string, error)
+	FindReference(details traderepublic.TimelineDetailsJson) (string, error)
+	FindSources(details traderepublic.TimelineDetailsJson) ([]string, error)
+	Direction() Direction
 }
 
 type GenericType struct {
+	titles traderepublic.Titles // Titles the details are rendered with, English when not set
+	fields map[string]FieldPath // Locations of the values, values without one are empty
+}
+
+// configure sets the titles the values are searched under and the locations of the values, the types
+// created by the rules get them from the resolver.
+func (t *GenericType) configure(titles traderepublic.Titles, fields map[string]FieldPath) {
+	t.titles = titles
+	t.fields = fields
 }
 
 func (t *GenericType) FindID(details traderepublic.TimelineDetailsJson) string {
@@ -50,343 +89,456 @@ func (t *GenericType) FindTimestamp(details traderepublic.TimelineDetailsJson) (
 	return header.Data.Timestamp, nil
 }
 
+func (t *GenericType) FindISIN(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("isin", details)
+}
+
+func (t *GenericType) FindShares(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("shares", details)
+}
+
+func (t *GenericType) FindSharePrice(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("sharePrice", details)
+}
+
+func (t *GenericType) FindFee(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("fee", details)
+}
+
+func (t *GenericType) FindTotal(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("total", details)
+}
+
+func (t *GenericType) FindYield(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("yield", details)
+}
+
+func (t *GenericType) FindGain(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("gain", details)
+}
+
+func (t *GenericType) FindTax(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("tax", details)
+}
+
+func (t *GenericType) FindGross(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("gross", details)
+}
+
+func (t *GenericType) FindAverageBalance(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("averageBalance", details)
+}
+
+func (t *GenericType) FindAnnualRate(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("annualRate", details)
+}
+
+func (t *GenericType) FindMerchant(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("merchant", details)
+}
+
+func (t *GenericType) FindOriginalAmount(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("originalAmount", details)
+}
+
+func (t *GenericType) FindExchangeRate(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("exchangeRate", details)
+}
+
+func (t *GenericType) FindCounterparty(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("counterparty", details)
+}
+
+func (t *GenericType) FindIBAN(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("iban", details)
+}
+
+func (t *GenericType) FindReference(details traderepublic.TimelineDetailsJson) (string, error) {
+	return t.find("reference", details)
+}
+
+func (t *GenericType) FindSources(_ traderepublic.TimelineDetailsJson) ([]string, error) {
+	return nil, nil
+}
+
+func (t *GenericType) Direction() Direction {
+	return DirectionDebit
+}
+
+// find reads the value at the location configured for the field, it is empty when none is configured.
+func (t *GenericType) find(field string, details traderepublic.TimelineDetailsJson) (string, error) {
+	path, ok := t.fields[field]
+	if !ok {
+		return "", nil
+	}
+
+	return path.find(details, t.titles)
+}
+
 type HeaderActionPayloadISINType struct {
 	GenericType
 }
 
+// FindISIN returns the instrument linked in the header, falling back to the one shown in the header icon.
 func (t *HeaderActionPayloadISINType) FindISIN(details traderepublic.TimelineDetailsJson) (string, error) {
+	isin, err := t.find("isin", details)
+	if err != nil || isin != "" {
+		return isin, err
+	}
+
 	header, err := details.SectionHeader()
 	if err != nil {
 		return "", fmt.Errorf("failed to find header section: %w", err)
 	}
 
-	return header.Action.Payload, nil
+	// Income transactions link no instrument, but their icon contains the ISIN.
+	isin, err = ExtractInstrumentISINFromIcon(header.Data.Icon)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract ISIN from icon: %w", err)
+	}
+
+	return isin, nil
 }
 
 type SavingsPlanType struct {
 	HeaderActionPayloadISINType
 }
 
-func (t *SavingsPlanType) FindShares(details traderepublic.TimelineDetailsJson) (string, error) {
-	overview, err := details.FindSection(traderepublic.SectionOverview)
-	if err != nil {
-		return "", fmt.Errorf("failed to find overview section: %w", err)
-	}
+func (t *SavingsPlanType) String() string {
+	return "Savings plan"
+}
 
-	trn, err := overview.FindData(traderepublic.DataTransaction)
-	if err != nil {
-		return "", fmt.Errorf("failed to find transaction data: %w", err)
-	}
+type SavingsPlanPre202502Type struct {
+	HeaderActionPayloadISINType
+}
 
-	return *trn.Detail.DisplayValue.Prefix, nil
+func (t *SavingsPlanPre202502Type) String() string {
+	return "Savings plan"
 }
 
-func (t *SavingsPlanType) FindSharePrice(details traderepublic.TimelineDetailsJson) (string, error) {
-	overview, err := details.FindSection(traderepublic.SectionOverview)
-	if err != nil {
-		return "", fmt.Errorf("failed to find overview section: %w", err)
-	}
+// BuyOrderType represents an executed buy order (ORDER_EXECUTED, TRADE_INVOICE and
+// trading_trade_executed events).
+type BuyOrderType struct {
+	HeaderActionPayloadISINType
+}
 
-	trn, err := overview.FindData(traderepublic.DataTransaction)
-	if err != nil {
-		return "", fmt.Errorf("failed to find transaction data: %w", err)
-	}
+func (t *BuyOrderType) String() string {
+	return string(TypeBuyOrder)
+}
 
-	return trn.Detail.DisplayValue.Text, nil
+// SellOrderType represents an executed sell or limit sell order (ORDER_EXECUTED, TRADE_INVOICE and
+// trading_trade_executed events), its realized performance is listed in the Performance section.
+type SellOrderType struct {
+	HeaderActionPayloadISINType
 }
 
-func (t *SavingsPlanType) FindFee(details traderepublic.TimelineDetailsJson) (string, error) {
-	overview, err := details.FindSection(traderepublic.SectionOverview)
-	if err != nil {
-		return "", fmt.Errorf("failed to find overview section: %w", err)
-	}
+func (t *SellOrderType) Direction() Direction {
+	return DirectionCredit
+}
 
-	fee, err := overview.FindData(traderepublic.DataFee)
-	if err != nil {
-		return "", fmt.Errorf("failed to find fee data: %w", err)
-	}
+func (t *SellOrderType) String() string {
+	return string(TypeSellOrder)
+}
 
-	return fee.Detail.Text, nil
+// CryptoBuyType represents an executed crypto buy order, settled with a CRYPTO_SECURITIES_SETTLEMENT document.
+type CryptoBuyType struct {
+	HeaderActionPayloadISINType
 }
 
-func (t *SavingsPlanType) FindTotal(details traderepublic.TimelineDetailsJson) (string, error) {
-	overview, err := details.FindSection(traderepublic.SectionOverview)
-	if err != nil {
-		return "", fmt.Errorf("failed to find overview section: %w", err)
-	}
+func (t *CryptoBuyType) String() string {
+	return string(TypeCryptoBuy)
+}
 
-	total, err := overview.FindData(traderepublic.DataTotal)
-	if err != nil {
-		return "", fmt.Errorf("failed to find total data: %w", err)
-	}
+// CryptoSellType represents an executed crypto sell order, its realized performance is listed in the Performance section.
+type CryptoSellType struct {
+	HeaderActionPayloadISINType
+}
 
-	return total.Detail.Text, nil
+func (t *CryptoSellType) Direction() Direction {
+	return DirectionCredit
 }
 
-func (t *SavingsPlanType) String() string {
-	return "Savings plan"
+func (t *CryptoSellType) String() string {
+	return string(TypeCryptoSell)
 }
 
-type SavingsPlanPre202502Type struct {
-	SavingsPlanType
+// BenefitType represents a purchase made by the benefits program, the card transactions that
+// funded it are listed as embedded timeline items.
+type BenefitType struct {
+	HeaderActionPayloadISINType
 }
 
-func (t *SavingsPlanPre202502Type) FindShares(details traderepublic.TimelineDetailsJson) (string, error) {
-	trnSection, err := details.FindSection(traderepublic.SectionTransaction)
-	if err != nil {
-		return "", fmt.Errorf("failed to find transaction section: %w", err)
-	}
+// FindSources returns the funding card transactions formatted as "title, amount, date".
+func (t *BenefitType) FindSources(details traderepublic.TimelineDetailsJson) ([]string, error) {
+	rows := details.BenefitRows()
+	sources := make([]string, 0, len(rows))
 
-	shares, err := trnSection.FindData(traderepublic.DataShares)
-	if err != nil {
-		return "", fmt.Errorf("failed to find shares data: %w", err)
+	for _, row := range rows {
+		source := row.Detail.Title
+		if row.Detail.Amount != nil {
+			source += ", " + *row.Detail.Amount
+		}
+
+		sources = append(sources, source+", "+row.Detail.Timestamp.Format(time.DateOnly))
 	}
 
-	return shares.Detail.Text, nil
+	return sources, nil
 }
 
-func (t *SavingsPlanPre202502Type) FindSharePrice(details traderepublic.TimelineDetailsJson) (string, error) {
-	trnSection, err := details.FindSection(traderepublic.SectionTransaction)
-	if err != nil {
-		return "", fmt.Errorf("failed to find transaction section: %w", err)
-	}
+// SavebackType represents an investment of the saveback bonus (benefits_saveback_execution events),
+// it is paid by Trade Republic so the cash account is not debited.
+type SavebackType struct {
+	BenefitType
+}
 
-	sharePrice, err := trnSection.FindData(traderepublic.DataSharePrice)
-	if err != nil {
-		return "", fmt.Errorf("failed to find shares data: %w", err)
-	}
+func (t *SavebackType) Direction() Direction {
+	return DirectionBenefit
+}
 
-	return sharePrice.Detail.Text, nil
+func (t *SavebackType) String() string {
+	return string(TypeSaveback)
 }
 
-func (t *SavingsPlanPre202502Type) FindFee(details traderepublic.TimelineDetailsJson) (string, error) {
-	trnSection, err := details.FindSection(traderepublic.SectionTransaction)
-	if err != nil {
-		return "", fmt.Errorf("failed to find transaction section: %w", err)
-	}
+// RoundUpType represents an investment of the spare change of card payments
+// (benefits_spare_change_execution events), it is debited from the cash account.
+type RoundUpType struct {
+	BenefitType
+}
 
-	fee, err := trnSection.FindData(traderepublic.DataFee)
-	if err != nil {
-		return "", fmt.Errorf("failed to find fee data: %w", err)
-	}
+func (t *RoundUpType) String() string {
+	return string(TypeRoundUp)
+}
 
-	return fee.Detail.Text, nil
+// DividendType represents cash income from dividends and other corporate actions
+// (ssp_corporate_action_invoice_cash and CREDIT events with a CA_INCOME_INVOICE document).
+// Total is the net amount credited after the withholding tax, gross is their sum.
+type DividendType struct {
+	HeaderActionPayloadISINType
 }
 
-func (t *SavingsPlanPre202502Type) FindTotal(details traderepublic.TimelineDetailsJson) (string, error) {
-	trnSection, err := details.FindSection(traderepublic.SectionTransaction)
+// FindGross returns the dividend before the withholding tax, the details only list the tax and the net total.
+func (t *DividendType) FindGross(details traderepublic.TimelineDetailsJson) (string, error) {
+	if _, ok := t.fields["gross"]; ok {
+		return t.find("gross", details)
+	}
+
+	totalStr, err := t.FindTotal(details)
+	if err != nil || totalStr == "" {
+		return "", err
+	}
+
+	total, err := ParseLocalizedFloat(totalStr, t.titles.Locale())
 	if err != nil {
-		return "", fmt.Errorf("failed to find transaction section: %w", err)
+		return "", fmt.Errorf("failed to parse float from total: %w", err)
 	}
 
-	total, err := trnSection.FindData(traderepublic.DataTotal)
+	taxStr, err := t.FindTax(details)
 	if err != nil {
-		return "", fmt.Errorf("failed to find total data: %w", err)
+		return "", err
 	}
 
-	return total.Detail.Text, nil
-}
+	tax := float64(0)
 
-// TransactionType represents the type of a transaction.
-type TransactionType string
+	if taxStr != "" {
+		tax, err = ParseLocalizedFloat(taxStr, t.titles.Locale())
+		if err != nil {
+			return "", fmt.Errorf("failed to parse float from tax: %w", err)
+		}
+	}
 
-const (
-	TypeUnknown              TransactionType = "unknown"          // Unknown transaction type
-	TypeIgnored              TransactionType = "ingored"          // Ignored transaction type
-	TypeSavingsplan          TransactionType = "Savings plan"     // Savings plan transaction
-	TypeSavingsplanPre202502 TransactionType = "Savings plan"     // Savings plan transaction
-	TypeCardPayment          TransactionType = "Card payment"     // Card payment transaction
-	TypeCardRefund           TransactionType = "Card refund"      // Card refund transaction
-	TypeBuyOrder             TransactionType = "Buy order"        // Buy order transaction
-	TypeSellOrder            TransactionType = "Sell order"       // Sell order transaction
-	TypeDividendsIncome      TransactionType = "Dividends income" // Dividends income transaction
-	TypeRoundUp              TransactionType = "Round up"         // Round up transaction
-	TypeSaveback             TransactionType = "Saveback"         // Saveback transaction
-	TypeDeposit              TransactionType = "Deposit"          // Deposit transaction
-	TypeWithdrawal           TransactionType = "Withdrawal"       // Withdrawal transaction
-	TypeInterestPayment      TransactionType = "Interest payment" // Interest payment transaction
-)
+	// Two decimals are never mistaken for a thousands separator when parsed back
+	return strconv.FormatFloat(total+math.Abs(tax), 'f', 2, 64), nil
+}
 
-// TypeResolver resolves the type of a transaction based on its details.
-type TypeResolver struct {
+func (t *DividendType) Direction() Direction {
+	return DirectionCredit
 }
 
-// NewTypeResolver creates a new instance of TypeResolver.
-func NewTypeResolver() *TypeResolver {
-	return &TypeResolver{}
+func (t *DividendType) String() string {
+	return string(TypeDividendsIncome)
 }
 
-// Resolve determines the type of a transaction from its details.
-func (r *TypeResolver) SetType(details traderepublic.TimelineDetailsJson, model *Model) error {
-	header, err := details.SectionHeader()
-	if err == nil {
-		if header.Data.Status == "canceled" {
-			return ErrCancelledTransactionReceived
-		}
-	}
+// CardType represents a transaction made with the Trade Republic card, it is listed in the Overview section.
+// Values paid in a foreign currency come with the original amount and the exchange rate applied.
+type CardType struct {
+	GenericType
+}
 
-	overview, err := details.FindSection(traderepublic.SectionOverview)
+// FindExchangeRate returns the rate of the foreign currency, the row reads like "1 € = 25.21 CZK".
+func (t *CardType) FindExchangeRate(details traderepublic.TimelineDetailsJson) (string, error) {
+	rate, err := t.find("exchangeRate", details)
 	if err != nil {
-		return fmt.Errorf("failed to find overview section: %w", err)
+		return "", err
 	}
 
-	// Check for ignored transactions
-	_, err = overview.FindData(traderepublic.DataCardVerification)
-	if err == nil {
-		return fmt.Errorf("%w: %s", ErrIgnoredTransactionReceived, details.Id)
+	_, value, found := strings.Cut(rate, "=")
+	if !found {
+		return rate, nil
 	}
 
-	// // Check for card payment transactions
-	// _, err = overview.FindData(traderepublic.DataCardPayment)
-	// if err == nil {
-	// 	model.Type = TypeCardPayment
+	return strings.TrimSpace(value), nil
+}
+
+// CardPaymentType represents a successful card payment (card_successful_transaction events).
+type CardPaymentType struct {
+	CardType
+}
+
+func (t *CardPaymentType) String() string {
+	return string(TypeCardPayment)
+}
+
+// CardRefundType represents a merchant refund credited back to the card (card_refund events).
+type CardRefundType struct {
+	CardType
+}
 
-	// 	return
-	// }
+func (t *CardRefundType) Direction() Direction {
+	return DirectionCredit
+}
 
-	// // Check for card refund transactions
-	// _, err = overview.FindData(traderepublic.DataCardRefund)
-	// if err == nil {
-	// 	model.Type = TypeCardRefund
+func (t *CardRefundType) String() string {
+	return string(TypeCardRefund)
+}
 
-	// 	return
-	// }
+// CardFailedType represents a declined card payment (card_failed_transaction events),
+// no money is moved so no total is read.
+type CardFailedType struct {
+	CardType
+}
 
-	// // Check for deposit transactions
-	// _, err = details.FindSection(traderepublic.SectionSender)
-	// if err == nil {
-	// 	model.Type = TypeDeposit
+func (t *CardFailedType) String() string {
+	return string(TypeCardFailed)
+}
+
+// CardVerificationType represents a card check made by a merchant
+// (card_successful_verification and card_failed_verification events).
+type CardVerificationType struct {
+	CardType
+}
+
+func (t *CardVerificationType) String() string {
+	return string(TypeCardVerification)
+}
 
-	// 	return
-	// }
-	// _, err = overview.FindData(traderepublic.DataFrom)
-	// if err == nil {
-	// 	model.Type = TypeDeposit
+// DepositType represents money received from a bank account (PAYMENT_INBOUND, INCOMING_TRANSFER and
+// INCOMING_TRANSFER_DELEGATION events).
+type DepositType struct {
+	GenericType
+}
 
-	// 	return
-	// }
+func (t *DepositType) Direction() Direction {
+	return DirectionCredit
+}
 
-	// // Check for withdrawal transactions
-	// _, err = overview.FindData(traderepublic.DataTo)
-	// if err == nil {
-	// 	model.Type = TypeWithdrawal
+func (t *DepositType) String() string {
+	return string(TypeDeposit)
+}
 
-	// 	return
-	// }
+// DirectDebitType represents money pulled from a bank account by SEPA direct debit
+// (PAYMENT_INBOUND_SEPA_DIRECT_DEBIT events).
+type DirectDebitType struct {
+	DepositType
+}
 
-	// payment, err := overview.FindData(traderepublic.DataPayment)
-	// if err == nil {
-	// 	if payment.Detail.Text == "Direct Debit" {
-	// 		model.Type = TypeDeposit
+func (t *DirectDebitType) String() string {
+	return string(TypeDirectDebit)
+}
 
-	// 		return
-	// 	}
-	// }
+// WithdrawalType represents money sent to a bank account (PAYMENT_OUTBOUND and
+// OUTGOING_TRANSFER_DELEGATION events).
+type WithdrawalType struct {
+	GenericType
+}
 
-	// Check for savings plan transactions
-	_, err = overview.FindData(traderepublic.DataSavingsPlan)
-	if err == nil {
-		model.Type = &SavingsPlanType{}
+func (t *WithdrawalType) String() string {
+	return string(TypeWithdrawal)
+}
 
-		return nil
-	}
+// InterestPaymentType represents the monthly interest paid on the cash balance (INTEREST_PAYOUT and
+// INTEREST_PAYOUT_CREATED events), total is the net amount credited after the tax withheld from the
+// accrued gross interest.
+type InterestPaymentType struct {
+	GenericType
+}
 
-	// // Check for dividends income transactions
-	// event, err := overview.FindData(traderepublic.DataEvent)
-	// if err == nil {
-	// 	switch event.Detail.Text {
-	// 	case "Income", "Cash dividend":
-	// 		model.Type = TypeDividendsIncome
+func (t *InterestPaymentType) Direction() Direction {
+	return DirectionCredit
+}
 
-	// 		return
-	// 	case "Tax Settlement":
-	// 		model.Type = TypeIgnored
+func (t *InterestPaymentType) String() string {
+	return string(TypeInterestPayment)
+}
 
-	// 		slog.Warn("ignored transaction type", "id", details.Id, "err", err, "info", "Tax Settlement")
+// TransactionType represents the type of a transaction.
+type TransactionType string
 
-	// 		return
-	// 	}
-	// }
+const (
+	TypeUnknown              TransactionType = "unknown"           // Unknown transaction type
+	TypeIgnored              TransactionType = "ingored"           // Ignored transaction type
+	TypeSavingsplan          TransactionType = "Savings plan"      // Savings plan transaction
+	TypeSavingsplanPre202502 TransactionType = "Savings plan"      // Savings plan transaction
+	TypeCardPayment          TransactionType = "Card payment"      // Card payment transaction
+	TypeCardRefund           TransactionType = "Card refund"       // Card refund transaction
+	TypeCardFailed           TransactionType = "Card failed"       // Declined card transaction
+	TypeCardVerification     TransactionType = "Card verification" // Card verification
+	TypeBuyOrder             TransactionType = "Buy order"         // Buy order transaction
+	TypeSellOrder            TransactionType = "Sell order"        // Sell order transaction
+	TypeCryptoBuy            TransactionType = "Crypto buy"        // Crypto buy order transaction
+	TypeCryptoSell           TransactionType = "Crypto sell"       // Crypto sell order transaction
+	TypeDividendsIncome      TransactionType = "Dividends income"  // Dividends income transaction
+	TypeRoundUp              TransactionType = "Round up"          // Round up transaction
+	TypeSaveback             TransactionType = "Saveback"          // Saveback transaction
+	TypeDeposit              TransactionType = "Deposit"           // Deposit transaction
+	TypeWithdrawal           TransactionType = "Withdrawal"        // Withdrawal transaction
+	TypeDirectDebit          TransactionType = "Direct debit"      // SEPA direct debit deposit transaction
+	TypeInterestPayment      TransactionType = "Interest payment"  // Interest payment transaction
+)
+
+// EventTypeLegacyMigrated is the event type of transactions migrated from the legacy timeline, they are
+// resolved from their details only.
+const EventTypeLegacyMigrated = "timeline_legacy_migrated_events"
 
-	// Check for buy order transactions
-	orderType, err := overview.FindData(traderepublic.DataOrderType)
-	if err == nil {
-		switch orderType.Detail.Text {
-		case "Savings plan":
-			model.Type = &SavingsPlanPre202502Type{}
+// TypeResolver resolves the type of a transaction based on its details.
+type TypeResolver struct {
+	rules  []Rule
+	fields map[string]map[string]FieldPath
+	titles traderepublic.Titles
+}
 
-			return nil
-			// 	case "Buy":
-			// 		model.Type = TypeBuyOrder
+// NewTypeResolver creates a new instance of TypeResolver evaluating the given rules on details rendered
+// in the locale, the titles are searched under the ones of the locale and the aliases of the rules.
+func NewTypeResolver(rules *RuleSet, locale traderepublic.Locale) *TypeResolver {
+	return &TypeResolver{
+		rules:  rules.Rules,
+		fields: rules.Fields,
+		titles: traderepublic.NewTitles(locale, rules.Aliases),
+	}
+}
 
-			// 		return
-			// 	case "Sell":
-			// 		model.Type = TypeSellOrder
+// SetType determines the type of a transaction from its details by evaluating the rules in order.
+func (r *TypeResolver) SetType(details traderepublic.TimelineDetailsJson, model *Model) error {
+	eventType := model.EventType
+	if eventType == EventTypeLegacyMigrated {
+		eventType = ""
+	}
 
-			// 		return
-			// 	case "Limit Sell":
-			// 		model.Type = TypeSellOrder
+	for _, rule := range r.rules {
+		if !rule.matches(details, eventType, r.titles) {
+			continue
+		}
 
-			// 		return
+		switch rule.Type {
+		case RuleKindCanceled:
+			return ErrCancelledTransactionReceived
+		case RuleKindIgnored:
+			return fmt.Errorf("%w: %s: %s", ErrIgnoredTransactionReceived, details.Id, rule.Name)
 		}
-	}
 
-	// // Check for interest payment transactions
-	// _, err = overview.FindData(traderepublic.DataAverageBalance)
-	// if err == nil {
-	// 	model.Type = TypeInterestPayment
-
-	// 	return
-	// }
-	// steps, err := details.SectionSteps()
-	// if err == nil {
-	// 	_, err = steps.FindStep(traderepublic.StepInterestPayment)
-	// 	if err == nil {
-	// 		model.Type = TypeInterestPayment
-
-	// 		return
-	// 	}
-	// }
-
-	// // Check for saveback transactions
-	// _, err = overview.FindData(traderepublic.DataSaveback)
-	// if err == nil {
-	// 	model.Type = TypeSaveback
-
-	// 	return
-	// }
-
-	// // Check for round up transactions
-	// _, err = overview.FindData(traderepublic.DataRoundUp)
-	// if err == nil {
-	// 	model.Type = TypeRoundUp
-
-	// 	return
-	// }
-
-	// // Check for limit sell transactions
-	// _, err = overview.FindData(traderepublic.DataLimitSell)
-	// if err == nil {
-	// 	model.Type = TypeSellOrder
-
-	// 	return
-	// }
-
-	// // Check for sell transactions
-	// _, err = overview.FindData(traderepublic.DataSell)
-	// if err == nil {
-	// 	model.Type = TypeSellOrder
-
-	// 	return
-	// }
-
-	// // Check for buy transactions
-	// _, err = overview.FindData(traderepublic.DataBuy)
-	// if err == nil {
-	// 	model.Type = TypeBuyOrder
-
-	// 	return
-	// }
+		model.Type = rule.newType(r.titles, r.fields[rule.Type])
+
+		return nil
+	}
 
 	return fmt.Errorf("%w: %s", ErrUnknownTransactionReceived, details.Id)
 }
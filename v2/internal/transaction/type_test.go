@@ -32,7 +32,7 @@ func TestTypeResolver_Resolve(t *testing.T) {
 		err = details.UnmarshalJSON(contents)
 		require.NoError(t, err)
 
-		resolver := transaction.NewTypeResolver()
+		resolver := transaction.NewTypeResolver(loadRules(t), traderepublic.LocaleEN)
 
 		t.Run("it resolves type in  "+entry.Name(), func(t *testing.T) {
 			t.Parallel()
@@ -46,3 +46,118 @@ func TestTypeResolver_Resolve(t *testing.T) {
 		})
 	}
 }
+
+func TestTypeResolver_SetType(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		filepath string
+		expected transaction.Type
+	}{
+		{filepath: "../../tests/fakes/fe9f80f9-329c-44db-bd98-22c192bd93fc.json", expected: &transaction.SavingsPlanPre202502Type{}},
+		{filepath: "../../tests/fakes/05d28e4e-e07e-424f-b5c8-a79815865dbd.json", expected: &transaction.BuyOrderType{}},
+		{filepath: "../../tests/fakes/deb6f4dc-893c-4f15-aa1d-edc97376952b.json", expected: &transaction.CryptoSellType{}},
+		{filepath: "../../tests/fakes/4b6d8f0a-2c4e-4a6c-8e0a-2c4e6a8c0e2a.json", expected: &transaction.SellOrderType{}},
+		{filepath: "../../tests/fakes/e3a5c7e9-1b3d-4f5a-9c7e-1a3c5e7a9c1e.json", expected: &transaction.CryptoBuyType{}},
+		{filepath: "../../tests/fakes/a0e4c36a-e0ee-4183-a725-09fb1c6b3c33.json", expected: &transaction.DividendType{}},
+		{filepath: "../../tests/fakes/3b7d3c1e-64a0-4f0c-9a43-2f6f7f0e5b11.json", expected: &transaction.CardPaymentType{}},
+		{filepath: "../../tests/fakes/8e2f95c4-0b7e-4c59-8d5c-1a0c6a9c2d47.json", expected: &transaction.CardPaymentType{}},
+		{filepath: "../../tests/fakes/c41a7d0b-2f55-4e3b-b1f6-6d9b0f4e7a82.json", expected: &transaction.CardRefundType{}},
+		{filepath: "../../tests/fakes/f0d6a1e9-7c3b-4a8e-9e25-4b1d8c6f3a90.json", expected: &transaction.CardFailedType{}},
+		{filepath: "../../tests/fakes/5a9e2c71-d3f8-4b06-a1e4-97c3b5d2f068.json", expected: &transaction.CardVerificationType{}},
+		{filepath: "../../tests/fakes/2d4f6b8a-1c3e-4a5b-9d7f-0e2c4a6b8d1f.json", expected: &transaction.DepositType{}},
+		{filepath: "../../tests/fakes/7e9a1b3c-5d7f-4e2a-8b4c-6d8e0f2a4b6c.json", expected: &transaction.WithdrawalType{}},
+		{filepath: "../../tests/fakes/b5c7d9e1-3f5a-4b7c-9e1a-3c5e7a9b1d3f.json", expected: &transaction.DirectDebitType{}},
+		{filepath: "../../tests/fakes/6c8e0a2b-4d6f-4a8c-b0d2-e4f6a8c0b2d4.json", expected: &transaction.InterestPaymentType{}},
+		{filepath: "../../tests/fakes/9f1b3d5e-7a9c-4b1d-8f3a-5c7e9b1d3f5a.json", expected: &transaction.InterestPaymentType{}},
+		{filepath: "../../tests/fakes/73fc417a-62ef-4179-a85e-9f3b29224567.json", expected: &transaction.SavebackType{}},
+		{filepath: "../../tests/fakes/265cb9c0-664a-45d4-b179-3061f196dd2a.json", expected: &transaction.RoundUpType{}},
+	}
+
+	resolver := transaction.NewTypeResolver(loadRules(t), traderepublic.LocaleEN)
+
+	for _, testCase := range testCases {
+		t.Run("it resolves type in "+filepath.Base(testCase.filepath), func(t *testing.T) {
+			t.Parallel()
+
+			contents, err := os.ReadFile(testCase.filepath)
+			require.NoError(t, err)
+
+			var details traderepublic.TimelineDetailsJson
+
+			err = details.UnmarshalJSON(contents)
+			require.NoError(t, err)
+
+			model := transaction.Model{}
+
+			err = resolver.SetType(details, &model)
+			require.NoError(t, err)
+
+			assert.IsType(t, testCase.expected, model.Type)
+		})
+	}
+}
+
+func TestTypeResolver_SetTypeIgnoresTaxSettlement(t *testing.T) {
+	t.Parallel()
+
+	details := traderepublic.TimelineDetailsJson{
+		Id: "tax-settlement",
+		Sections: []any{
+			map[string]any{
+				"title": "Overview",
+				"type":  "table",
+				"data": []any{
+					map[string]any{
+						"title": "Event",
+						"style": "plain",
+						"detail": map[string]any{
+							"text": "Tax Settlement",
+							"type": "text",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	model := transaction.Model{}
+
+	err := transaction.NewTypeResolver(loadRules(t), traderepublic.LocaleEN).SetType(details, &model)
+	require.ErrorIs(t, err, transaction.ErrIgnoredTransactionReceived)
+	assert.Nil(t, model.Type)
+}
+
+func TestTypeResolver_SetTypeWithLocale(t *testing.T) {
+	t.Parallel()
+
+	details := traderepublic.TimelineDetailsJson{
+		Id: "steuerkorrektur",
+		Sections: []any{
+			map[string]any{
+				"title": "Übersicht",
+				"type":  "table",
+				"data": []any{
+					map[string]any{
+						"title": "Ereignis",
+						"style": "plain",
+						"detail": map[string]any{
+							"text": "Steuerkorrektur",
+							"type": "text",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Resolvers of different locales in one process do not share their titles
+	german := transaction.NewTypeResolver(loadRules(t), traderepublic.LocaleDE)
+	english := transaction.NewTypeResolver(loadRules(t), traderepublic.LocaleEN)
+
+	err := german.SetType(details, &transaction.Model{})
+	require.ErrorIs(t, err, transaction.ErrIgnoredTransactionReceived)
+
+	err = english.SetType(details, &transaction.Model{})
+	require.ErrorIs(t, err, transaction.ErrUnknownTransactionReceived)
+}
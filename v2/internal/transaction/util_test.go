@@ -85,6 +85,22 @@ func TestParseFloatFromResponse(t *testing.T) {
 		{input: "1921.89", expected: 1921.89},
 		{input: "10000.00", expected: 10000},
 		{input: "138.26 €", expected: 138.26},
+		{input: "+ €223.55", expected: 223.55},
+		{input: "-€9.89", expected: -9.89},
+		{input: "-4.61 %", expected: -4.61},
+		{input: "- 1.001,77 €", expected: -1001.77},
+		{input: "€1,234.00", expected: 1234},
+		{input: "-€12,345.67", expected: -12345.67},
+		{input: "1.234 €", expected: 1234},
+		{input: "1\u202f234,56 €", expected: 1234.56},
+		{input: "€40.301", expected: 40.301},
+		{input: "0,301 €", expected: 0.301},
+		{input: "1.234\u00a0€", expected: 1234},
+		{input: "4,13\u00a0€", expected: 4.13},
+		{input: "1.234\u202f€", expected: 1234},
+		{input: "€\u00a01,234.00", expected: 1234},
+		{input: "\u221212,00 €", expected: -12},
+		{input: "\u2212 €9.89", expected: -9.89},
 	}
 
 	for i, testCase := range testCases {
@@ -94,3 +110,39 @@ func TestParseFloatFromResponse(t *testing.T) {
 		assert.Equal(t, testCase.expected, actual, fmt.Sprintf("case %d", i))
 	}
 }
+
+func TestParseCurrencyFromResponse(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "€5.95", expected: "EUR"},
+		{input: "CZK 155.00", expected: "CZK"},
+		{input: "$12.00", expected: "USD"},
+		{input: "Free", expected: ""},
+	}
+
+	for i, testCase := range testCases {
+		assert.Equal(t, testCase.expected, transaction.ParseCurrencyFromResponse(testCase.input), fmt.Sprintf("case %d", i))
+	}
+}
+
+func TestMaskIBAN(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "DE89370400440532013000", expected: "DE89 •••• 3000"},
+		{input: "DE89 3704 0044 0532 0130 00", expected: "DE89 •••• 3000"},
+		{input: "DE89 •••• 3000", expected: "DE89 •••• 3000"},
+		{input: "", expected: ""},
+	}
+
+	for i, testCase := range testCases {
+		assert.Equal(t, testCase.expected, transaction.MaskIBAN(testCase.input), fmt.Sprintf("case %d", i))
+	}
+}
@@ -9,10 +9,17 @@ import (
 	"time"
 
 	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/internal"
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
 )
 
 var ErrPatternMismatch = errors.New("value did not match the pattern")
 
+var (
+	numberPattern           = regexp.MustCompile(`\d(?:[\d.,'\x{00a0}\x{202f}]*\d)?`)
+	leadingCurrencyPattern  = regexp.MustCompile(`[€$£][\s\x{00a0}\x{202f}]?\d`)
+	trailingCurrencyPattern = regexp.MustCompile(`\d[\s\x{00a0}\x{202f}]?(?:[€$£]|EUR\b)`)
+)
+
 type CSVDateTime struct {
 	time.Time
 }
@@ -59,26 +66,114 @@ func ExtractInstrumentISINFromIcon(src string) (string, error) {
 	return matches[1], nil
 }
 
-func ParseFloatFromResponse(src string) (float64, error) {
-	pattern := regexp.MustCompile(`(\d+(?:\.\d+)*|\d+)(?:,(\d+))?`)
-	matches := pattern.FindStringSubmatch(src)
+// ParseCurrencyFromResponse returns the ISO code of the currency found in the given text,
+// an empty string is returned when the text holds no currency.
+func ParseCurrencyFromResponse(src string) string {
+	symbols := map[string]string{"€": "EUR", "$": "USD", "£": "GBP"}
 
-	if len(matches) == 0 {
-		return 0, ErrPatternMismatch
+	code := regexp.MustCompile(`\b[A-Z]{3}\b`).FindString(src)
+	if code != "" {
+		return code
 	}
 
-	wholePart := matches[1]
-	decimalPart := matches[2]
-	strFloat := wholePart
+	for symbol, code := range symbols {
+		if strings.Contains(src, symbol) {
+			return code
+		}
+	}
+
+	return ""
+}
 
-	if decimalPart != "" {
-		strFloat = strings.ReplaceAll(wholePart, ".", "") + "." + decimalPart
+// MaskIBAN hides all but the country code, check digits and the last four characters of an IBAN,
+// values that are already masked are returned as they are.
+func MaskIBAN(iban string) string {
+	const visible = 4
+
+	compact := strings.ReplaceAll(iban, " ", "")
+	if strings.ContainsAny(compact, "•*") || len(compact) <= 2*visible {
+		return iban
 	}
 
+	return compact[:visible] + " •••• " + compact[len(compact)-visible:]
+}
+
+// ParseFloatFromResponse extracts the first number found in the given text rendered in English.
+func ParseFloatFromResponse(src string) (float64, error) {
+	return ParseLocalizedFloat(src, traderepublic.LocaleEN)
+}
+
+// ParseLocalizedFloat extracts the first number found in the given text rendered in the locale,
+// the number is negated when the text starts with a hyphen or minus sign.
+func ParseLocalizedFloat(src string, locale traderepublic.Locale) (float64, error) {
+	number := numberPattern.FindString(src)
+
+	if number == "" {
+		return 0, ErrPatternMismatch
+	}
+
+	strFloat := normalizeNumber(number, decimalSeparator(src, locale))
+
 	value, err := strconv.ParseFloat(strFloat, 64)
 	if err != nil {
 		return 0, fmt.Errorf("could not parse float from '%s': %w", src, err)
 	}
 
+	trimmed := strings.TrimSpace(src)
+	if strings.HasPrefix(trimmed, "-") || strings.HasPrefix(trimmed, "\u2212") {
+		value = -value
+	}
+
 	return value, nil
 }
+
+// decimalSeparator guesses the decimal separator of the amount in the given text. Amounts with a
+// leading currency symbol are English formatted and ones with a trailing symbol are continental,
+// the locale decides otherwise. This keeps amounts right when the requested locale is not honored.
+func decimalSeparator(src string, locale traderepublic.Locale) rune {
+	if leadingCurrencyPattern.MatchString(src) {
+		return '.'
+	}
+
+	if trailingCurrencyPattern.MatchString(src) {
+		return ','
+	}
+
+	return locale.DecimalSeparator()
+}
+
+// normalizeNumber converts a localized number into the format understood by strconv.
+// A single separator followed by three digits is ambiguous and only treated as decimal one
+// when it is the expected decimal separator or the whole part is zero.
+func normalizeNumber(number string, decimal rune) string {
+	number = strings.NewReplacer("'", "", "\u00a0", "", "\u202f", "").Replace(number)
+
+	lastDot := strings.LastIndex(number, ".")
+	lastComma := strings.LastIndex(number, ",")
+
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		separator := max(lastDot, lastComma)
+		whole := strings.NewReplacer(".", "", ",", "").Replace(number[:separator])
+
+		return whole + "." + number[separator+1:]
+	case lastDot < 0 && lastComma < 0:
+		return number
+	}
+
+	separator := "."
+	if lastComma >= 0 {
+		separator = ","
+	}
+
+	parts := strings.Split(number, separator)
+	if len(parts) > 2 {
+		return strings.Join(parts, "")
+	}
+
+	if len(parts[1]) == 3 && parts[0] != "0" && rune(separator[0]) != decimal {
+		return parts[0] + parts[1]
+	}
+
+	return parts[0] + "." + parts[1]
+}
@@ -0,0 +1,36 @@
+package writer
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic writes to a temporary file next to the target and renames it over the target, so
+// readers and interrupted runs never see a half written file.
+func WriteFileAtomic(filePath string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Chmod(filePermissions)
+	}
+
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filePath)
+}
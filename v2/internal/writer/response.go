@@ -13,14 +13,23 @@ const (
 )
 
 type ResponseWriter struct {
+	baseDir string
 }
 
-func NewResponseWriter() *ResponseWriter {
-	return &ResponseWriter{}
+// NewResponseWriter creates a writer placing responses under baseDir.
+// If baseDir is empty, it uses the default directory from internal.ResponseBaseDir.
+func NewResponseWriter(baseDir string) *ResponseWriter {
+	if baseDir == "" {
+		baseDir = internal.ResponseBaseDir
+	}
+
+	return &ResponseWriter{
+		baseDir: baseDir,
+	}
 }
 
 func (w *ResponseWriter) Bytes(filename string, data []byte) error {
-	formattedFilename := filepath.Join(internal.ResponseBaseDir, filename+".json")
+	formattedFilename := filepath.Join(w.baseDir, filename+".json")
 
 	err := os.MkdirAll(filepath.Dir(formattedFilename), os.ModePerm)
 	if err != nil {
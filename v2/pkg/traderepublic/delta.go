@@ -0,0 +1,58 @@
+package traderepublic
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidDelta = errors.New("invalid delta")
+
+// ApplyDelta rebuilds a payload from the previous one and the delta of a continue (C) message.
+// The delta is a tab separated list of operations: =N copies the next N characters of the previous
+// payload, -N skips them and +text inserts the URL encoded text. Lengths count characters, not bytes.
+func ApplyDelta(previous, delta string) (string, error) {
+	var (
+		result strings.Builder
+		offset int
+	)
+
+	characters := []rune(previous)
+
+	for _, operation := range strings.Split(delta, "\t") {
+		if operation == "" {
+			continue
+		}
+
+		if operation[0] == '+' {
+			// Encoded like a query, the plus sign of the operation is decoded to a trimmed space
+			text, err := url.QueryUnescape(operation)
+			if err != nil {
+				return "", fmt.Errorf("%w: %w", ErrInvalidDelta, err)
+			}
+
+			result.WriteString(strings.TrimSpace(text))
+
+			continue
+		}
+
+		length, err := strconv.Atoi(operation[1:])
+		if err != nil || length < 0 || offset+length > len(characters) {
+			return "", fmt.Errorf("%w: operation %q", ErrInvalidDelta, operation)
+		}
+
+		switch operation[0] {
+		case '=':
+			result.WriteString(string(characters[offset : offset+length]))
+		case '-':
+		default:
+			return "", fmt.Errorf("%w: operation %q", ErrInvalidDelta, operation)
+		}
+
+		offset += length
+	}
+
+	return result.String(), nil
+}
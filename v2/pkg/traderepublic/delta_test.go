@@ -0,0 +1,80 @@
+package traderepublic_test
+
+import (
+	"testing"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyDelta(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		previous    string
+		delta       string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "Replaces a value",
+			previous: `{"bid":{"price":101.5},"open":100}`,
+			delta:    "=16\t-5\t+102.25\t=13",
+			expected: `{"bid":{"price":102.25},"open":100}`,
+		},
+		{
+			name:     "Decodes the inserted text",
+			previous: `{"name":""}`,
+			delta:    "=9\t+Tom%20%26%20Co\t=2",
+			expected: `{"name":"Tom & Co"}`,
+		},
+		{
+			name:     "Counts characters of non-ASCII text",
+			previous: `{"amount":"4,13 €","title":"Müller"}`,
+			delta:    "=11\t-4\t+5%2C20\t=21",
+			expected: `{"amount":"5,20 €","title":"Müller"}`,
+		},
+		{
+			name:        "Copies past the characters of the payload",
+			previous:    `{"a":"€"}`,
+			delta:       "=10",
+			expectError: true,
+		},
+		{
+			name:     "Keeps the payload",
+			previous: `{"a":1}`,
+			delta:    "=7",
+			expected: `{"a":1}`,
+		},
+		{
+			name:        "Copies past the payload",
+			previous:    `{"a":1}`,
+			delta:       "=8",
+			expectError: true,
+		},
+		{
+			name:        "Unknown operation",
+			previous:    `{"a":1}`,
+			delta:       "*7",
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := traderepublic.ApplyDelta(testCase.previous, testCase.delta)
+			if testCase.expectError {
+				assert.ErrorIs(t, err, traderepublic.ErrInvalidDelta)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, actual)
+		})
+	}
+}
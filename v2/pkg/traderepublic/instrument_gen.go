@@ -134,6 +134,9 @@ type InstrumentJson struct {
 	// Company corresponds to the JSON schema field "company".
 	Company *Company `json:"company,omitempty" yaml:"company,omitempty" mapstructure:"company,omitempty"`
 
+	// DerivativeInfo corresponds to the JSON schema field "derivativeInfo".
+	DerivativeInfo *InstrumentJsonDerivativeInfo `json:"derivativeInfo,omitempty" yaml:"derivativeInfo,omitempty" mapstructure:"derivativeInfo,omitempty"`
+
 	// Descriptions corresponds to the JSON schema field "descriptions".
 	Descriptions *map[string]interface{} `json:"descriptions,omitempty" yaml:"descriptions,omitempty" mapstructure:"descriptions,omitempty"`
 
@@ -161,6 +164,12 @@ type InstrumentJson struct {
 	// Isin corresponds to the JSON schema field "isin".
 	Isin string `json:"isin" yaml:"isin" mapstructure:"isin"`
 
+	// Issuer corresponds to the JSON schema field "issuer".
+	Issuer *string `json:"issuer,omitempty" yaml:"issuer,omitempty" mapstructure:"issuer,omitempty"`
+
+	// IssuerDisplayName corresponds to the JSON schema field "issuerDisplayName".
+	IssuerDisplayName *string `json:"issuerDisplayName,omitempty" yaml:"issuerDisplayName,omitempty" mapstructure:"issuerDisplayName,omitempty"`
+
 	// Jurisdictions corresponds to the JSON schema field "jurisdictions".
 	Jurisdictions map[string]interface{} `json:"jurisdictions" yaml:"jurisdictions" mapstructure:"jurisdictions"`
 
@@ -201,6 +210,51 @@ type InstrumentJson struct {
 	Wkn *string `json:"wkn,omitempty" yaml:"wkn,omitempty" mapstructure:"wkn,omitempty"`
 }
 
+type InstrumentJsonDerivativeInfo struct {
+	// CategoryType corresponds to the JSON schema field "categoryType".
+	CategoryType *string `json:"categoryType,omitempty" yaml:"categoryType,omitempty" mapstructure:"categoryType,omitempty"`
+
+	// ProductCategoryName corresponds to the JSON schema field "productCategoryName".
+	ProductCategoryName *string `json:"productCategoryName,omitempty" yaml:"productCategoryName,omitempty" mapstructure:"productCategoryName,omitempty"`
+
+	// Properties corresponds to the JSON schema field "properties".
+	Properties *InstrumentJsonDerivativeInfoProperties `json:"properties,omitempty" yaml:"properties,omitempty" mapstructure:"properties,omitempty"`
+
+	// Underlying corresponds to the JSON schema field "underlying".
+	Underlying *InstrumentJsonDerivativeInfoUnderlying `json:"underlying,omitempty" yaml:"underlying,omitempty" mapstructure:"underlying,omitempty"`
+}
+
+type InstrumentJsonDerivativeInfoProperties struct {
+	// Barrier corresponds to the JSON schema field "barrier".
+	Barrier *float64 `json:"barrier,omitempty" yaml:"barrier,omitempty" mapstructure:"barrier,omitempty"`
+
+	// Currency corresponds to the JSON schema field "currency".
+	Currency *string `json:"currency,omitempty" yaml:"currency,omitempty" mapstructure:"currency,omitempty"`
+
+	// Expiry corresponds to the JSON schema field "expiry".
+	Expiry *string `json:"expiry,omitempty" yaml:"expiry,omitempty" mapstructure:"expiry,omitempty"`
+
+	// Leverage corresponds to the JSON schema field "leverage".
+	Leverage *float64 `json:"leverage,omitempty" yaml:"leverage,omitempty" mapstructure:"leverage,omitempty"`
+
+	// OptionType corresponds to the JSON schema field "optionType".
+	OptionType *string `json:"optionType,omitempty" yaml:"optionType,omitempty" mapstructure:"optionType,omitempty"`
+
+	// Strike corresponds to the JSON schema field "strike".
+	Strike *float64 `json:"strike,omitempty" yaml:"strike,omitempty" mapstructure:"strike,omitempty"`
+}
+
+type InstrumentJsonDerivativeInfoUnderlying struct {
+	// Isin corresponds to the JSON schema field "isin".
+	Isin *string `json:"isin,omitempty" yaml:"isin,omitempty" mapstructure:"isin,omitempty"`
+
+	// Name corresponds to the JSON schema field "name".
+	Name *string `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name,omitempty"`
+
+	// ShortName corresponds to the JSON schema field "shortName".
+	ShortName *string `json:"shortName,omitempty" yaml:"shortName,omitempty" mapstructure:"shortName,omitempty"`
+}
+
 type InstrumentJsonFundInfo struct {
 	// Category corresponds to the JSON schema field "category".
 	Category InstrumentJsonFundInfoCategory `json:"category" yaml:"category" mapstructure:"category"`
@@ -402,6 +456,7 @@ type InstrumentJsonTaxProviderInfoWmdaten struct {
 type InstrumentJsonTypeId string
 
 const InstrumentJsonTypeIdCrypto InstrumentJsonTypeId = "crypto"
+const InstrumentJsonTypeIdDerivative InstrumentJsonTypeId = "derivative"
 const InstrumentJsonTypeIdEtf InstrumentJsonTypeId = "etf"
 const InstrumentJsonTypeIdFund InstrumentJsonTypeId = "fund"
 const InstrumentJsonTypeIdStock InstrumentJsonTypeId = "stock"
@@ -411,6 +466,7 @@ var enumValues_InstrumentJsonTypeId = []interface{}{
 	"fund",
 	"stock",
 	"etf",
+	"derivative",
 }
 
 // UnmarshalJSON implements json.Unmarshaler.
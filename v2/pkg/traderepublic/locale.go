@@ -0,0 +1,203 @@
+package traderepublic
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Locale is the language Trade Republic renders titles, texts and amounts in.
+type Locale string
+
+const (
+	LocaleEN Locale = "en"
+	LocaleDE Locale = "de"
+	LocaleFR Locale = "fr"
+	LocaleIT Locale = "it"
+	LocaleES Locale = "es"
+)
+
+var ErrUnsupportedLocale = errors.New("unsupported locale")
+
+// localizedTitles maps the titles and texts used in this package to the ones sent in other locales.
+// Translations missing here can be added through the aliases of the transaction rules, titles are
+// always compared exactly so the short ones do not match longer titles.
+var localizedTitles = map[Locale]map[string][]string{
+	LocaleEN: {},
+	LocaleDE: {
+		"Overview":           {"Übersicht"},
+		"Transaction":        {"Transaktion", "Geschäft"},
+		"Sender":             {"Absender"},
+		"Savings Plan":       {"Sparplan"},
+		"Interest payment":   {"Zinszahlung"},
+		"From":               {"Von"},
+		"To":                 {"An", "Empfänger"},
+		"Payment":            {"Zahlung"},
+		"Buy":                {"Kauf"},
+		"Sell":               {"Verkauf"},
+		"Limit Sell":         {"Limit-Verkauf"},
+		"Card verification":  {"Kartenverifizierung"},
+		"Card payment":       {"Kartenzahlung"},
+		"Card refund":        {"Kartenerstattung"},
+		"Average balance":    {"Durchschnittlicher Saldo"},
+		"Order Type":         {"Orderart", "Ordertyp"},
+		"Event":              {"Ereignis"},
+		"Asset":              {"Wertpapier", "Basiswert"},
+		"Shares":             {"Anteile", "Aktien"},
+		"Share price":        {"Aktienkurs", "Anteilspreis"},
+		"Fee":                {"Gebühr"},
+		"Profit":             {"Rendite"},
+		"Gain":               {"Gewinn"},
+		"Loss":               {"Verlust"},
+		"Total":              {"Gesamt", "Summe"},
+		"Tax":                {"Steuern"},
+		"Dividend per share": {"Dividende je Aktie", "Dividende pro Aktie"},
+		"Merchant":           {"Händler"},
+		"Original amount":    {"Ursprünglicher Betrag"},
+		"Exchange rate":      {"Wechselkurs"},
+		"Reference":          {"Verwendungszweck", "Referenz"},
+		"Amount":             {"Betrag"},
+		"Annual rate":        {"Jahreszins"},
+		"Accrued":            {"Angefallen"},
+		"Savings plan":       {"Sparplan"},
+		"Income":             {"Ertrag"},
+		"Cash dividend":      {"Bardividende"},
+		"Tax Settlement":     {"Steuerkorrektur"},
+		"Direct Debit":       {"Lastschrift"},
+	},
+	LocaleFR: {
+		"Overview":           {"Aperçu"},
+		"Sender":             {"Expéditeur"},
+		"Savings Plan":       {"Plan d'investissement"},
+		"Interest payment":   {"Versement des intérêts"},
+		"From":               {"De"},
+		"To":                 {"À", "Destinataire"},
+		"Payment":            {"Paiement"},
+		"Buy":                {"Achat"},
+		"Sell":               {"Vente"},
+		"Card payment":       {"Paiement par carte"},
+		"Card refund":        {"Remboursement par carte"},
+		"Average balance":    {"Solde moyen"},
+		"Order Type":         {"Type d'ordre"},
+		"Event":              {"Événement"},
+		"Asset":              {"Actif"},
+		"Shares":             {"Titres", "Parts"},
+		"Share price":        {"Cours"},
+		"Fee":                {"Frais"},
+		"Profit":             {"Rendement"},
+		"Gain":               {"Plus-value"},
+		"Loss":               {"Moins-value"},
+		"Tax":                {"Impôts"},
+		"Dividend per share": {"Dividende par action"},
+		"Merchant":           {"Commerçant"},
+		"Original amount":    {"Montant initial"},
+		"Exchange rate":      {"Taux de change"},
+		"Name":               {"Nom"},
+		"Reference":          {"Référence"},
+		"Amount":             {"Montant"},
+		"Annual rate":        {"Taux annuel"},
+		"Savings plan":       {"Plan d'investissement"},
+		"Income":             {"Revenu"},
+		"Cash dividend":      {"Dividende en espèces"},
+		"Direct Debit":       {"Prélèvement"},
+	},
+	LocaleIT: {
+		"Overview":           {"Panoramica"},
+		"Transaction":        {"Transazione"},
+		"Sender":             {"Mittente"},
+		"Savings Plan":       {"Piano di accumulo"},
+		"Interest payment":   {"Pagamento degli interessi"},
+		"From":               {"Da"},
+		"To":                 {"A", "Destinatario"},
+		"Payment":            {"Pagamento"},
+		"Buy":                {"Acquisto"},
+		"Sell":               {"Vendita"},
+		"Card payment":       {"Pagamento con carta"},
+		"Card refund":        {"Rimborso carta"},
+		"Average balance":    {"Saldo medio"},
+		"Order Type":         {"Tipo di ordine"},
+		"Event":              {"Evento"},
+		"Shares":             {"Quote", "Azioni"},
+		"Share price":        {"Prezzo per azione"},
+		"Fee":                {"Commissione"},
+		"Profit":             {"Rendimento"},
+		"Gain":               {"Guadagno"},
+		"Loss":               {"Perdita"},
+		"Total":              {"Totale"},
+		"Tax":                {"Tasse"},
+		"Dividend per share": {"Dividendo per azione"},
+		"Merchant":           {"Esercente"},
+		"Original amount":    {"Importo originale"},
+		"Exchange rate":      {"Tasso di cambio"},
+		"Name":               {"Nome"},
+		"Reference":          {"Riferimento"},
+		"Amount":             {"Importo"},
+		"Annual rate":        {"Tasso annuo"},
+		"Savings plan":       {"Piano di accumulo"},
+		"Income":             {"Provento"},
+		"Cash dividend":      {"Dividendo in contanti"},
+		"Direct Debit":       {"Addebito diretto"},
+	},
+	LocaleES: {
+		"Overview":           {"Resumen"},
+		"Transaction":        {"Transacción"},
+		"Performance":        {"Rentabilidad"},
+		"Sender":             {"Remitente"},
+		"Savings Plan":       {"Plan de inversión"},
+		"Interest payment":   {"Pago de intereses"},
+		"From":               {"De"},
+		"To":                 {"A", "Destinatario"},
+		"Payment":            {"Pago"},
+		"Buy":                {"Compra"},
+		"Sell":               {"Venta"},
+		"Card payment":       {"Pago con tarjeta"},
+		"Card refund":        {"Reembolso de tarjeta"},
+		"Average balance":    {"Saldo medio"},
+		"Order Type":         {"Tipo de orden"},
+		"Event":              {"Evento"},
+		"Asset":              {"Activo"},
+		"Shares":             {"Acciones", "Participaciones"},
+		"Share price":        {"Precio por acción"},
+		"Fee":                {"Comisión"},
+		"Profit":             {"Rendimiento"},
+		"Gain":               {"Ganancia"},
+		"Loss":               {"Pérdida"},
+		"Tax":                {"Impuestos"},
+		"Dividend per share": {"Dividendo por acción"},
+		"Merchant":           {"Comercio"},
+		"Original amount":    {"Importe original"},
+		"Exchange rate":      {"Tipo de cambio"},
+		"Name":               {"Nombre"},
+		"Reference":          {"Referencia"},
+		"Amount":             {"Importe"},
+		"Annual rate":        {"Tipo anual"},
+		"Savings plan":       {"Plan de inversión"},
+		"Income":             {"Ingreso"},
+		"Cash dividend":      {"Dividendo en efectivo"},
+		"Direct Debit":       {"Adeudo directo"},
+	},
+}
+
+// Locales returns the supported locales.
+func Locales() []Locale {
+	return []Locale{LocaleEN, LocaleDE, LocaleFR, LocaleIT, LocaleES}
+}
+
+// ParseLocale returns the supported locale of the given language code.
+func ParseLocale(value string) (Locale, error) {
+	locale := Locale(value)
+	if !slices.Contains(Locales(), locale) {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedLocale, value)
+	}
+
+	return locale, nil
+}
+
+// DecimalSeparator returns the character separating the decimal part of the amounts in the locale.
+func (l Locale) DecimalSeparator() rune {
+	if l == LocaleEN {
+		return '.'
+	}
+
+	return ','
+}
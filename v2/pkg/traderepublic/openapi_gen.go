@@ -28,6 +28,20 @@ const (
 // ServerUrlTradeRepublicRESTAPI defines the Server URL for Trade Republic REST API
 const ServerUrlTradeRepublicRESTAPI = "https://api.traderepublic.com/api/v1"
 
+// Defines values for APILoginResponseConfirmationMethod.
+const (
+	APILoginResponseConfirmationMethodAPP APILoginResponseConfirmationMethod = "APP"
+	APILoginResponseConfirmationMethodSMS APILoginResponseConfirmationMethod = "SMS"
+)
+
+// Defines values for APILoginStatusResponseStatus.
+const (
+	APILoginStatusResponseStatusCONFIRMED APILoginStatusResponseStatus = "CONFIRMED"
+	APILoginStatusResponseStatusEXPIRED   APILoginStatusResponseStatus = "EXPIRED"
+	APILoginStatusResponseStatusPENDING   APILoginStatusResponseStatus = "PENDING"
+	APILoginStatusResponseStatusREJECTED  APILoginStatusResponseStatus = "REJECTED"
+)
+
 // APILoginRequest defines model for APILoginRequest.
 type APILoginRequest struct {
 	// PhoneNumber User's phone number
@@ -39,10 +53,28 @@ type APILoginRequest struct {
 
 // APILoginResponse defines model for APILoginResponse.
 type APILoginResponse struct {
+	// ConfirmationMethod How the login is confirmed, with an OTP or in the paired mobile app
+	ConfirmationMethod *APILoginResponseConfirmationMethod `json:"confirmationMethod,omitempty"`
+
+	// CountdownInSeconds Seconds until the login process expires
+	CountdownInSeconds *int `json:"countdownInSeconds,omitempty"`
+
 	// ProcessId Process ID for OTP verification
 	ProcessId *string `json:"processId,omitempty"`
 }
 
+// APILoginResponseConfirmationMethod How the login is confirmed, with an OTP or in the paired mobile app
+type APILoginResponseConfirmationMethod string
+
+// APILoginStatusResponse defines model for APILoginStatusResponse.
+type APILoginStatusResponse struct {
+	// Status State of a login confirmed in the paired mobile app
+	Status APILoginStatusResponseStatus `json:"status"`
+}
+
+// APILoginStatusResponseStatus State of a login confirmed in the paired mobile app
+type APILoginStatusResponseStatus string
+
 // LoginJSONRequestBody defines body for Login for application/json ContentType.
 type LoginJSONRequestBody = APILoginRequest
 
@@ -124,6 +156,9 @@ type ClientInterface interface {
 
 	Login(ctx context.Context, body LoginJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// GetLoginStatus request
+	GetLoginStatus(ctx context.Context, processId string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// VerifyOTP request
 	VerifyOTP(ctx context.Context, processId string, otp string, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -155,6 +190,18 @@ func (c *Client) Login(ctx context.Context, body LoginJSONRequestBody, reqEditor
 	return c.Client.Do(req)
 }
 
+func (c *Client) GetLoginStatus(ctx context.Context, processId string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetLoginStatusRequest(c.Server, processId)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) VerifyOTP(ctx context.Context, processId string, otp string, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewVerifyOTPRequest(c.Server, processId, otp)
 	if err != nil {
@@ -219,6 +266,40 @@ func NewLoginRequestWithBody(server string, contentType string, body io.Reader)
 	return req, nil
 }
 
+// NewGetLoginStatusRequest generates requests for GetLoginStatus
+func NewGetLoginStatusRequest(server string, processId string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "processId", runtime.ParamLocationPath, processId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/auth/web/login/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewVerifyOTPRequest generates requests for VerifyOTP
 func NewVerifyOTPRequest(server string, processId string, otp string) (*http.Request, error) {
 	var err error
@@ -335,6 +416,9 @@ type ClientWithResponsesInterface interface {
 
 	LoginWithResponse(ctx context.Context, body LoginJSONRequestBody, reqEditors ...RequestEditorFn) (*LoginResponse, error)
 
+	// GetLoginStatusWithResponse request
+	GetLoginStatusWithResponse(ctx context.Context, processId string, reqEditors ...RequestEditorFn) (*GetLoginStatusResponse, error)
+
 	// VerifyOTPWithResponse request
 	VerifyOTPWithResponse(ctx context.Context, processId string, otp string, reqEditors ...RequestEditorFn) (*VerifyOTPResponse, error)
 
@@ -364,6 +448,28 @@ func (r LoginResponse) StatusCode() int {
 	return 0
 }
 
+type GetLoginStatusResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *APILoginStatusResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetLoginStatusResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetLoginStatusResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type VerifyOTPResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
@@ -423,6 +529,15 @@ func (c *ClientWithResponses) LoginWithResponse(ctx context.Context, body LoginJ
 	return ParseLoginResponse(rsp)
 }
 
+// GetLoginStatusWithResponse request returning *GetLoginStatusResponse
+func (c *ClientWithResponses) GetLoginStatusWithResponse(ctx context.Context, processId string, reqEditors ...RequestEditorFn) (*GetLoginStatusResponse, error) {
+	rsp, err := c.GetLoginStatus(ctx, processId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetLoginStatusResponse(rsp)
+}
+
 // VerifyOTPWithResponse request returning *VerifyOTPResponse
 func (c *ClientWithResponses) VerifyOTPWithResponse(ctx context.Context, processId string, otp string, reqEditors ...RequestEditorFn) (*VerifyOTPResponse, error) {
 	rsp, err := c.VerifyOTP(ctx, processId, otp, reqEditors...)
@@ -467,6 +582,32 @@ func ParseLoginResponse(rsp *http.Response) (*LoginResponse, error) {
 	return response, nil
 }
 
+// ParseGetLoginStatusResponse parses an HTTP response from a GetLoginStatusWithResponse call
+func ParseGetLoginStatusResponse(rsp *http.Response) (*GetLoginStatusResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetLoginStatusResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest APILoginStatusResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseVerifyOTPResponse parses an HTTP response from a VerifyOTPWithResponse call
 func ParseVerifyOTPResponse(rsp *http.Response) (*VerifyOTPResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
@@ -502,21 +643,25 @@ func ParseRefreshSessionResponse(rsp *http.Response) (*RefreshSessionResponse, e
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/8xWTW/jNhD9KwRboC2gtZJuT7pl2x6MbhMjdtuD1yhoaWRxI5FczsiBa+i/F0PJH5Ll",
-	"bHIo0JtNDjlv3rw31F6mtnLWgCGUyV5iWkClws+72fSj3WjzCF9qQOIl560DTxpCgCusgfu6WoPnvxlg",
-	"6rUjbY1M5B8I/jsUIUaYNiiStHMgE4nktdnIJpJOm6tnZ9P7yyNNJD18qbWHTCbLHob2ttXxiF1/hpQ4",
-	"y6kUdNYgjNTibQqI0+wSzazdEtNfRG69eFjMxBa8znWqQsQYxAGCJpIIae017eZMcJvTQ+4Bi4V9ghES",
-	"HttdQbwt7JqUNpAJlROMgmAiZWrtkwYZSaMqBkD+7y7LCaZy+jfYyYAJUVtzBcC83R0HUDKdL2Ttrr7M",
-	"ylxok9uRgn+dL8TdbBpYXniVgXgEV69LnQpVUwGGumonn8yi0CjQQXqkQFhT7oQ2aVlngIIKEOFGMJmz",
-	"2hBGwlgK63/Bem7TJyBRAaLagGCQOPkU8GoqGfAAwQGdjOQWPLaQbyc3kxtm0jowymmZyPeTm8l7lqKi",
-	"InQ5ZuzxM6zjljMWm23t1K9/ajRpRR32ECw6WYpnTUXPS0KZrDMIyzgwwOKVH7vG+Na1H2y241SpNQQm",
-	"ZFXOlR1n8We05mR7/vWth1wm8pv4NBfibijEw4nQ9N1Ivoaw0JosVP/jzc1/kL5zccjfJzEECKxTpi2v",
-	"S9E59ogykgWoDHxANwd693Mr3q/I/3uddy1h3Z2u58bYmjjHDzI6K2Q4ExjqTy0Z/TwfVCb8gVCOuR0Z",
-	"iIZFZL3+B7LeNJHJchVJrKtK+d2x/JfUQmqDPDjveo6SK751INV4f5yKTby35Jrr4v0zDKNOu0w4giFB",
-	"Nvyvz96CC8GGk7uHxSyYxqsKKHRn+cIc9pCC3kImcm+r/ixi350m0WmsD4X6Uq+iYeoHA+9IVyCcQny2",
-	"Pjsh2Gol5r/Px9Nbcm9KvBp3zwDMYPafyfHt6mZh+POXBv8HKm4lIVpNvE6wh/cm2csN0NXXtBMo9rxd",
-	"ozabsN5j4kKp3R3z49P29V4daO4uhuysWeXu9e26h+c+6Fd06Y0dGH6PLFfsg/4nwnLV9Bp1+EY5e+2v",
-	"ditk89txb19/bGtfykQWRA6TOFZOT4hjfRc6SW3Fq/H2Vjar5t8AAAD//7FD/JzPCgAA",
+	"H4sIAAAAAAAC/8xWW48aRxP9K636PimJNGZw7Cfe1rtkQ+JlEZCLtEZRM1Mw7Z3pbnfXQMiK/x51zwBz",
+	"g11HTuQ3mL7UqVOnTtcTRCrTSqIkC4MnsFGCGfc/ryaj92ot5BQ/5WjJfdJGaTQk0G/QiZI4zrMlGvc3",
+	"RhsZoUkoCQP4xaL5xjK/h8liUwC00wgDsGSEXMM+AC3k2bOT0bh9ZB+AwU+5MBjD4KGGobhtcTyilh8x",
+	"IhfllIrVSlps5xIpuRIm4w7BHVKi4jasH9WWUYIsdVcxYVl5COOAbQUljEt2P58wZZiQfqfmDifL1FKk",
+	"yLjWEADKPHPIZ3czcMgmsGglGUCkckmx2sqRnGGkZGzbeMoFlksSaQWZNipCaxn+qYVBe+JQSMI1Gs97",
+	"sWfUkeakPD66YStlfEYbNGIlIs9OZ0nOMj4jTrk9z7v16x25ESdkasV4mdSR65dwOxmOb0bjWwjg+n78",
+	"w2h6N7yBAKbDn4bXc/9z+PtkNB3ewOI5fZX42qLaB2Axyo2g3cz1TJGOwZVBm8zVI3boelqsMnLLTC2J",
+	"C4kx4yvCTp5db0Ck1KNACEDyzAEg80cZ5VQJrsXPuAOPyVqh5BkAs2K1G4Dn+ULU8up2VMeFkCvVkfBw",
+	"NmdXk5EX0tzwGNkUdb5MRcR4TglKKrPtfZDzRFhmNUZHCpiS6Y4JGaV5jNZX3d+IMtZKSLIBk4r8999w",
+	"OVPRIxLL0Fq+RuZA2t4Hj1dQ6gA3EBzQQQAbNLaA/LrX7/Udk0qj5FrAAN70+r03EIDmlPgqhw57uMVl",
+	"WHDmVK0stfMfSUGCU4m93p3eMar2yLiMS89zHeIZcP0J78vCmMKI36l4V1oWofRRudZpyVn40Sp5cnL3",
+	"6/8GVzCA/4Unqw+LVRs2TX5fbwAyOfoPRf/67L/v9/+F8EWAIn6dRL+B2TxytK3ylJWmdEQZQII8RuPR",
+	"zZBeXRfifUb+34rVycor17vCqJxcjO8gqCTSdAoH9W2/347zjsfMHAh1e153vHHSiUgZ8RfGNTeBwcMi",
+	"AJtnGTe7Y/qX1EJ8bZ1XXdU6Chbu1oZUw6ej8e8dpjVSl01RbmShWdv04S0XJOTad3T10bxky3U53yJV",
+	"ngbfV4ZnSL6ADxdeI4MRig3GbGVUVrcr15onszrmCE0tXyrn4j/QeeM5PK/2AzefqWwnClN9ZSxTMsIz",
+	"Y8sL1P22S7mPUm1PRja6uajfWySW1pP6h4INnxTp/Xm3/dW/nqXZOoewKImR8v/zyjzakqQ/ubufT74e",
+	"NQbN0PcSX5HIXIdZu1UmPiHYCM6KcbIjvCL9JdqgAaYxrFT880uI9iuw3UISrNDEywR7GJDO+6rPsRSo",
+	"rT1GuXWm6r7XmGgptbxjdpzFnq/VgebyYowrxUp3Ly/XGLd10C+o0mdWoDlAPyxcH9Rn2ofFvlaow1Bd",
+	"GU/PVstHM5vu3j4/HeYmhQEkRNoOwpBr0SO315Rbe5HK3Ndw8xr2i/3fAwDnscmZUw8AAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -8,36 +8,42 @@ import (
 )
 
 type PublisherInterface interface {
-	Subscribe(topic string) <-chan []byte
-	Publish(msg []byte, topic string)
+	Subscribe(topic string) <-chan SubscriptionResult
+	Publish(msg SubscriptionResult, topic string)
 	Close(topic string)
 }
 
+// subscriberBuffer is how many results wait for the subscriber before stale stream updates are dropped.
+const subscriberBuffer = 16
+
 type Publisher struct {
-	subscribers map[string]chan []byte
-	mu          *sync.RWMutex
+	subscribers map[string]chan SubscriptionResult
+	mu          *sync.Mutex
 }
 
 func NewPublisher() *Publisher {
 	return &Publisher{
-		subscribers: make(map[string]chan []byte),
-		mu:          &sync.RWMutex{},
+		subscribers: make(map[string]chan SubscriptionResult),
+		mu:          &sync.Mutex{},
 	}
 }
 
-func (p *Publisher) Subscribe(topic string) <-chan []byte {
+func (p *Publisher) Subscribe(topic string) <-chan SubscriptionResult {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	ch := make(chan []byte)
+	ch := make(chan SubscriptionResult, subscriberBuffer)
 	p.subscribers[topic] = ch
 
 	return ch
 }
 
-func (p *Publisher) Publish(msg []byte, topic string) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+// Publish queues the result for the subscriber of the topic without waiting for it to be received. When the
+// subscriber falls behind, the oldest queued result is dropped: streams deliver the full payload on every
+// update and other subscriptions receive a single result.
+func (p *Publisher) Publish(msg SubscriptionResult, topic string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
 	ch, ok := p.subscribers[topic]
 	if !ok {
@@ -46,9 +52,26 @@ func (p *Publisher) Publish(msg []byte, topic string) {
 		return
 	}
 
-	ch <- msg
+	select {
+	case ch <- msg:
+		return
+	default:
+	}
+
+	select {
+	case <-ch:
+		slog.Warn("subscriber is falling behind, dropped stale result", "topic", topic)
+	default:
+	}
+
+	select {
+	case ch <- msg:
+	default:
+		slog.Error("could not queue result", "topic", topic)
+	}
 }
 
+// Close closes the channel of the topic, results queued before can still be received.
 func (p *Publisher) Close(topic string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -52,7 +52,7 @@ func (mr *MockPublisherInterfaceMockRecorder) Close(topic any) *gomock.Call {
 }
 
 // Publish mocks base method.
-func (m *MockPublisherInterface) Publish(msg []byte, topic string) {
+func (m *MockPublisherInterface) Publish(msg SubscriptionResult, topic string) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "Publish", msg, topic)
 }
@@ -64,10 +64,10 @@ func (mr *MockPublisherInterfaceMockRecorder) Publish(msg, topic any) *gomock.Ca
 }
 
 // Subscribe mocks base method.
-func (m *MockPublisherInterface) Subscribe(topic string) <-chan []byte {
+func (m *MockPublisherInterface) Subscribe(topic string) <-chan SubscriptionResult {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Subscribe", topic)
-	ret0, _ := ret[0].(<-chan []byte)
+	ret0, _ := ret[0].(<-chan SubscriptionResult)
 	return ret0
 }
 
@@ -0,0 +1,64 @@
+package traderepublic_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublisher_Publish(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it does not wait for the subscriber", func(t *testing.T) {
+		t.Parallel()
+
+		publisher := traderepublic.NewPublisher()
+		ch := publisher.Subscribe("1")
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			for i := range 100 {
+				publisher.Publish(traderepublic.SubscriptionResult{Data: []byte(strconv.Itoa(i))}, "1")
+			}
+
+			publisher.Close("1")
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			assert.FailNow(t, "publishing to a subscriber that does not receive blocked")
+		}
+
+		var last string
+
+		for result := range ch {
+			last = string(result.Data)
+		}
+
+		// Stale updates are dropped, the latest one is kept
+		assert.Equal(t, "99", last)
+	})
+
+	t.Run("it keeps results queued before closing", func(t *testing.T) {
+		t.Parallel()
+
+		publisher := traderepublic.NewPublisher()
+		ch := publisher.Subscribe("1")
+
+		publisher.Publish(traderepublic.SubscriptionResult{Data: []byte("data")}, "1")
+		publisher.Close("1")
+
+		result, open := <-ch
+		assert.True(t, open)
+		assert.Equal(t, "data", string(result.Data))
+
+		_, open = <-ch
+		assert.False(t, open)
+	})
+}
@@ -0,0 +1,94 @@
+package traderepublic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when the subscribed item does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrRateLimited is returned when TR rejects a subscription because too many were sent.
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrSubscriptionFailed is returned for subscriptions rejected for any other reason.
+	ErrSubscriptionFailed = errors.New("subscription failed")
+
+	// ErrSubscriptionTimeout is returned when no data is received for a subscription in time.
+	ErrSubscriptionTimeout = errors.New("subscription timed out")
+)
+
+// subscriptionErrorCodes maps the error codes of error (E) messages to the errors reported for them.
+var subscriptionErrorCodes = map[string]error{
+	authErrorCode:         ErrAuthRequired,
+	"NOT_FOUND":           ErrNotFound,
+	"UNKNOWN_ID":          ErrNotFound,
+	"TOO_MANY_REQUESTS":   ErrRateLimited,
+	"RATE_LIMIT_EXCEEDED": ErrRateLimited,
+}
+
+// SubscriptionResult is delivered on the channel of a subscription, carrying either the data or the
+// reason the subscription failed.
+type SubscriptionResult struct {
+	Data []byte
+	Err  error
+}
+
+// SubscriptionError is a subscription rejected with an error (E) message, it wraps one of ErrAuthRequired,
+// ErrNotFound, ErrRateLimited or ErrSubscriptionFailed depending on the error code.
+type SubscriptionError struct {
+	Code    string
+	Message string
+	err     error
+}
+
+func (e *SubscriptionError) Error() string {
+	if e.Code == "" {
+		return e.err.Error()
+	}
+
+	return fmt.Sprintf("%s: %s %s", e.err, e.Code, e.Message)
+}
+
+func (e *SubscriptionError) Unwrap() error {
+	return e.err
+}
+
+// parseSubscriptionError creates the error of an error message, its payload lists the errors as
+// {"errors":[{"errorCode":"...","errorMessage":"..."}]}.
+func parseSubscriptionError(data string) *SubscriptionError {
+	var payload struct {
+		Errors []struct {
+			ErrorCode    string `json:"errorCode"`
+			ErrorMessage string `json:"errorMessage"`
+		} `json:"errors"`
+	}
+
+	subErr := &SubscriptionError{err: ErrSubscriptionFailed}
+
+	if json.Unmarshal([]byte(data), &payload) != nil || len(payload.Errors) == 0 {
+		// Unknown payloads are matched on the code only
+		for code, err := range subscriptionErrorCodes {
+			if strings.Contains(data, code) {
+				subErr.Code = code
+				subErr.err = err
+			}
+		}
+
+		subErr.Message = data
+
+		return subErr
+	}
+
+	subErr.Code = payload.Errors[0].ErrorCode
+	subErr.Message = payload.Errors[0].ErrorMessage
+
+	if err, found := subscriptionErrorCodes[subErr.Code]; found {
+		subErr.err = err
+	}
+
+	return subErr
+}
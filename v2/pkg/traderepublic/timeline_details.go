@@ -7,6 +7,9 @@ import (
 	"slices"
 )
 
+// TrendNegative is the trend value of rows representing a loss.
+const TrendNegative = "negative"
+
 // Error constants for section and data item not found.
 var (
 	ErrSliceElementNotFound = errors.New("slice element not found")
@@ -44,11 +47,75 @@ var (
 	DataFee              = dataTitles{"Fee"}         // Title map for commission in payment details
 	DataProfit           = dataTitles{"Profit"}
 	DataGain             = dataTitles{"Gain"}
+	DataLoss             = dataTitles{"Loss"}
 	DataTotal            = dataTitles{"Total"} // Title map for total in payment details
 	DataTax              = dataTitles{"Tax"}
 	DataDividendPerShare = dataTitles{"Dividend per share"}
+	DataMerchant         = dataTitles{"Merchant"}
+	DataOriginalAmount   = dataTitles{"Original amount"}
+	DataExchangeRate     = dataTitles{"Exchange rate"}
+	DataName             = dataTitles{"Name"}
+	DataIBAN             = dataTitles{"IBAN"}
+	DataReference        = dataTitles{"Reference"}
+	DataAmount           = dataTitles{"Amount"}
+	DataAnnualRate       = dataTitles{"Annual rate"}
+	DataAccrued          = dataTitles{"Accrued"}
 )
 
+// Titles are the alternative titles and texts sections, data items and steps are found under besides
+// the English ones defined in this package, e.g. the ones of the locale the details are rendered in.
+// The zero value finds the English titles only.
+type Titles struct {
+	locale  Locale
+	aliases map[string][]string
+}
+
+// NewTitles creates the titles of the locale extended by the given aliases keyed by the English titles,
+// English titles keep matching in case the requested locale is not honored.
+func NewTitles(locale Locale, aliases map[string][]string) Titles {
+	titles := Titles{
+		locale:  locale,
+		aliases: map[string][]string{},
+	}
+
+	for _, source := range []map[string][]string{localizedTitles[locale], aliases} {
+		for title, alternatives := range source {
+			for _, alternative := range alternatives {
+				if !slices.Contains(titles.aliases[title], alternative) {
+					titles.aliases[title] = append(titles.aliases[title], alternative)
+				}
+			}
+		}
+	}
+
+	return titles
+}
+
+// Locale returns the locale the titles are rendered in, English by default.
+func (t Titles) Locale() Locale {
+	if t.locale == "" {
+		return LocaleEN
+	}
+
+	return t.locale
+}
+
+// Expand extends the given titles or texts with their alternatives, they are compared exactly so short
+// alternatives never match longer titles.
+func (t Titles) Expand(search []string) []string {
+	if len(t.aliases) == 0 {
+		return search
+	}
+
+	expanded := slices.Clone(search)
+
+	for _, title := range search {
+		expanded = append(expanded, t.aliases[title]...)
+	}
+
+	return expanded
+}
+
 // sectionTitles is a type alias for string representing a table section title.
 type sectionTitles []string
 
@@ -102,16 +169,90 @@ func (s *TableSection) FindData(titles dataTitles) (PaymentRow, error) {
 	return item, nil
 }
 
-func (s *StepsSection) FindStep(title string) (StepItem, error) {
+// FindCryptoData retrieves a crypto transaction row based on the provided titles from the table section.
+func (s *TableSection) FindCryptoData(titles dataTitles) (CryptoTransactionRow, error) {
+	var item CryptoTransactionRow
+
+	err := findSliceElement(s.Data, &item, titles)
+	if err != nil {
+		return CryptoTransactionRow{}, fmt.Errorf("%w with titles %v", ErrDataItemNotFound, titles)
+	}
+
+	return item, nil
+}
+
+// HasDocument reports whether a document of the given postbox type is attached to the timeline details.
+func (d *TimelineDetailsJson) HasDocument(postboxType DocumentRowPostboxType) bool {
+	for _, element := range d.Sections {
+		var section struct {
+			Data []any  `json:"data"`
+			Type string `json:"type"`
+		}
+
+		err := unmarshal(element, &section)
+		if err != nil || section.Type != "documents" {
+			continue
+		}
+
+		for _, data := range section.Data {
+			row, ok := data.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			if row["postboxType"] == string(postboxType) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsNegative reports whether the row value has to be treated as a negative number.
+func (r PaymentRow) IsNegative() bool {
+	return r.Detail.Trend != nil && *r.Detail.Trend == TrendNegative
+}
+
+// BenefitRows retrieves the embedded timeline items of all table sections, benefit executions list
+// the card transactions that funded them this way.
+func (d *TimelineDetailsJson) BenefitRows() []BenefitRow {
+	var rows []BenefitRow
+
+	for _, element := range d.Sections {
+		var section TableSection
+
+		err := unmarshal(element, &section)
+		if err != nil || section.Type != "table" {
+			continue
+		}
+
+		for _, data := range section.Data {
+			var row BenefitRow
+
+			err := unmarshal(data, &row)
+			if err != nil || row.Detail.Type != "embeddedTimelineItem" {
+				continue
+			}
+
+			rows = append(rows, row)
+		}
+	}
+
+	return rows
+}
+
+// FindStep retrieves the step having one of the titles.
+func (s *StepsSection) FindStep(titles []string) (StepItem, error) {
 	for _, step := range s.Steps {
-		if step.Content.Title != title {
+		if !slices.Contains(titles, step.Content.Title) {
 			continue
 		}
 
 		return step, nil
 	}
 
-	return StepItem{}, fmt.Errorf("%w with title %s", ErrStepNotFound, title)
+	return StepItem{}, fmt.Errorf("%w with titles %v", ErrStepNotFound, titles)
 }
 
 // findSliceElement searches for a slice element that matches the provided search criteria.
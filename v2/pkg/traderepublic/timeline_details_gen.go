@@ -203,13 +203,13 @@ func (j *BenefitRowDetail) UnmarshalJSON(value []byte) error {
 		return err
 	}
 	if plain.Amount != nil {
-		if matched, _ := regexp.MatchString(`^[0-9]+,[0-9]{2} €$`, string(*plain.Amount)); !matched {
-			return fmt.Errorf("field %s pattern match: must match %s", "Amount", `^[0-9]+,[0-9]{2} €$`)
+		if matched, _ := regexp.MatchString(`^(€[0-9]+\.[0-9]{2}|[0-9]+,[0-9]{2} €)$`, string(*plain.Amount)); !matched {
+			return fmt.Errorf("field %s pattern match: must match %s", "Amount", `^(€[0-9]+\.[0-9]{2}|[0-9]+,[0-9]{2} €)$`)
 		}
 	}
 	if plain.Icon != nil {
-		if matched, _ := regexp.MatchString(`^logos/[A-Z0-9]+/v2$`, string(*plain.Icon)); !matched {
-			return fmt.Errorf("field %s pattern match: must match %s", "Icon", `^logos/[A-Z0-9]+/v2$`)
+		if matched, _ := regexp.MatchString(`^logos/[A-Za-z0-9_-]+/v2$`, string(*plain.Icon)); !matched {
+			return fmt.Errorf("field %s pattern match: must match %s", "Icon", `^logos/[A-Za-z0-9_-]+/v2$`)
 		}
 	}
 	*j = BenefitRowDetail(plain)
@@ -409,8 +409,8 @@ func (j *CryptoTransactionRowDetail) UnmarshalJSON(value []byte) error {
 		return err
 	}
 	if plain.Text != nil {
-		if matched, _ := regexp.MatchString(`^([0-9]+,[0-9]{2} [€$]|[0-9]+,[0-9]{6}|[+-]? ?[0-9]+,[0-9]{2} [€$]|[0-9]+,[0-9]{2} %|[A-Za-z ]+)$`, string(*plain.Text)); !matched {
-			return fmt.Errorf("field %s pattern match: must match %s", "Text", `^([0-9]+,[0-9]{2} [€$]|[0-9]+,[0-9]{6}|[+-]? ?[0-9]+,[0-9]{2} [€$]|[0-9]+,[0-9]{2} %|[A-Za-z ]+)$`)
+		if matched, _ := regexp.MatchString(`^([0-9]+,[0-9]{2} [€$]|[0-9]+,[0-9]+|[+-]? ?[0-9]+,[0-9]{2} [€$]|[0-9]+,[0-9]{2} %|[+-]? ?[€$][0-9]+\.[0-9]+|[0-9]+\.[0-9]+|[0-9]+\.[0-9]{2} %|[A-Za-z ]+)$`, string(*plain.Text)); !matched {
+			return fmt.Errorf("field %s pattern match: must match %s", "Text", `^([0-9]+,[0-9]{2} [€$]|[0-9]+,[0-9]+|[+-]? ?[0-9]+,[0-9]{2} [€$]|[0-9]+,[0-9]{2} %|[+-]? ?[€$][0-9]+\.[0-9]+|[0-9]+\.[0-9]+|[0-9]+\.[0-9]{2} %|[A-Za-z ]+)$`)
 		}
 	}
 	*j = CryptoTransactionRowDetail(plain)
@@ -452,12 +452,19 @@ type CryptoTransactionRowTitle string
 const CryptoTransactionRowTitleAktienkurs CryptoTransactionRowTitle = "Aktienkurs"
 const CryptoTransactionRowTitleAnteile CryptoTransactionRowTitle = "Anteile"
 const CryptoTransactionRowTitleAsset CryptoTransactionRowTitle = "Asset"
+const CryptoTransactionRowTitleFee CryptoTransactionRowTitle = "Fee"
+const CryptoTransactionRowTitleGain CryptoTransactionRowTitle = "Gain"
 const CryptoTransactionRowTitleGebühr CryptoTransactionRowTitle = "Gebühr"
 const CryptoTransactionRowTitleGesamt CryptoTransactionRowTitle = "Gesamt"
 const CryptoTransactionRowTitleGewinn CryptoTransactionRowTitle = "Gewinn"
+const CryptoTransactionRowTitleOrderType CryptoTransactionRowTitle = "Order Type"
 const CryptoTransactionRowTitleOrderart CryptoTransactionRowTitle = "Orderart"
+const CryptoTransactionRowTitleProfit CryptoTransactionRowTitle = "Profit"
 const CryptoTransactionRowTitleRendite CryptoTransactionRowTitle = "Rendite"
+const CryptoTransactionRowTitleSharePrice CryptoTransactionRowTitle = "Share price"
+const CryptoTransactionRowTitleShares CryptoTransactionRowTitle = "Shares"
 const CryptoTransactionRowTitleStatus CryptoTransactionRowTitle = "Status"
+const CryptoTransactionRowTitleTotal CryptoTransactionRowTitle = "Total"
 
 var enumValues_CryptoTransactionRowTitle = []interface{}{
 	"Status",
@@ -469,6 +476,13 @@ var enumValues_CryptoTransactionRowTitle = []interface{}{
 	"Aktienkurs",
 	"Gebühr",
 	"Gesamt",
+	"Order Type",
+	"Profit",
+	"Gain",
+	"Shares",
+	"Share price",
+	"Fee",
+	"Total",
 }
 
 // UnmarshalJSON implements json.Unmarshaler.
@@ -1218,6 +1232,9 @@ type PaymentRowDetail struct {
 	// Text corresponds to the JSON schema field "text".
 	Text string `json:"text" yaml:"text" mapstructure:"text"`
 
+	// Trend corresponds to the JSON schema field "trend".
+	Trend *string `json:"trend,omitempty" yaml:"trend,omitempty" mapstructure:"trend,omitempty"`
+
 	// Type corresponds to the JSON schema field "type".
 	Type string `json:"type" yaml:"type" mapstructure:"type"`
 }
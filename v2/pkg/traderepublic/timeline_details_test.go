@@ -307,3 +307,139 @@ func getTestData(t *testing.T) []testCase {
 		},
 	}
 }
+
+func TestTableSection_FindCryptoData(t *testing.T) {
+	t.Parallel()
+
+	contents, err := os.ReadFile("../../tests/fakes/deb6f4dc-893c-4f15-aa1d-edc97376952b.json")
+	require.NoError(t, err)
+
+	var details traderepublic.TimelineDetailsJson
+
+	err = details.UnmarshalJSON(contents)
+	require.NoError(t, err)
+
+	transaction, err := details.FindSection(traderepublic.SectionTransaction)
+	require.NoError(t, err)
+
+	shares, err := transaction.FindCryptoData(traderepublic.DataShares)
+	require.NoError(t, err)
+	require.NotNil(t, shares.Detail.Text)
+	assert.Equal(t, "424.993643", *shares.Detail.Text)
+
+	_, err = transaction.FindCryptoData(traderepublic.DataTax)
+	assert.ErrorIs(t, err, traderepublic.ErrDataItemNotFound)
+}
+
+func TestTimelineDetailsJson_HasDocument(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		filepath string
+		expected bool
+	}{
+		{filepath: "../../tests/fakes/deb6f4dc-893c-4f15-aa1d-edc97376952b.json", expected: true},
+		{filepath: "../../tests/fakes/05d28e4e-e07e-424f-b5c8-a79815865dbd.json", expected: false},
+	}
+
+	for _, testCase := range testCases {
+		contents, err := os.ReadFile(testCase.filepath)
+		require.NoError(t, err)
+
+		var details traderepublic.TimelineDetailsJson
+
+		err = details.UnmarshalJSON(contents)
+		require.NoError(t, err)
+
+		actual := details.HasDocument(traderepublic.DocumentRowPostboxTypeCRYPTOSECURITIESSETTLEMENT)
+		assert.Equal(t, testCase.expected, actual, testCase.filepath)
+	}
+}
+
+func TestTitles(t *testing.T) {
+	t.Parallel()
+
+	details := traderepublic.TimelineDetailsJson{
+		Sections: []any{
+			map[string]any{
+				"title": "Buchungsübersicht",
+				"type":  "table",
+				"data": []any{
+					map[string]any{
+						"title":  "Gebühr",
+						"style":  "plain",
+						"detail": map[string]any{"text": "€1.00", "type": "text"},
+					},
+				},
+			},
+		},
+	}
+
+	titles := traderepublic.NewTitles(traderepublic.LocaleEN, map[string][]string{
+		"Transaction": {"Buchungsübersicht"},
+		"Fee":         {"Gebühr"},
+	})
+
+	section, err := details.FindSection(titles.Expand(traderepublic.SectionTransaction))
+	require.NoError(t, err)
+
+	fee, err := section.FindData(titles.Expand(traderepublic.DataFee))
+	require.NoError(t, err)
+	assert.Equal(t, "€1.00", fee.Detail.Text)
+
+	// Aliases are not shared with other titles
+	_, err = details.FindSection(traderepublic.Titles{}.Expand(traderepublic.SectionTransaction))
+	require.ErrorIs(t, err, traderepublic.ErrSectionNotFound)
+}
+
+func TestTitles_Locale(t *testing.T) {
+	t.Parallel()
+
+	details := traderepublic.TimelineDetailsJson{
+		Sections: []any{
+			map[string]any{
+				"title": "Übersicht",
+				"type":  "table",
+				"data": []any{
+					map[string]any{
+						"title":  "Anteile",
+						"style":  "plain",
+						"detail": map[string]any{"text": "2", "type": "text"},
+					},
+					map[string]any{
+						"title":  "Orderart",
+						"style":  "plain",
+						"detail": map[string]any{"text": "Kauf", "type": "text"},
+					},
+				},
+			},
+		},
+	}
+
+	titles := traderepublic.NewTitles(traderepublic.LocaleDE, nil)
+	assert.Equal(t, traderepublic.LocaleDE, titles.Locale())
+	assert.Equal(t, ',', titles.Locale().DecimalSeparator())
+	assert.Equal(t, traderepublic.LocaleEN, traderepublic.Titles{}.Locale())
+
+	overview, err := details.FindSection(titles.Expand(traderepublic.SectionOverview))
+	require.NoError(t, err)
+
+	orderType, err := overview.FindData(titles.Expand(traderepublic.DataOrderType))
+	require.NoError(t, err)
+	assert.Contains(t, titles.Expand([]string{"Buy"}), orderType.Detail.Text)
+
+	// "An" is the German title of the recipient, it must not match "Anteile"
+	_, err = overview.FindData(titles.Expand(traderepublic.DataTo))
+	require.ErrorIs(t, err, traderepublic.ErrDataItemNotFound)
+}
+
+func TestParseLocale(t *testing.T) {
+	t.Parallel()
+
+	locale, err := traderepublic.ParseLocale("de")
+	require.NoError(t, err)
+	assert.Equal(t, traderepublic.LocaleDE, locale)
+
+	_, err = traderepublic.ParseLocale("xx")
+	assert.ErrorIs(t, err, traderepublic.ErrUnsupportedLocale)
+}
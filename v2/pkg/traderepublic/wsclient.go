@@ -9,9 +9,11 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -28,6 +30,16 @@ const (
 
 	// Minimum parts in a message.
 	minMessageParts = 2
+
+	// Error code of subscriptions rejected because of an expired session.
+	authErrorCode = "AUTHENTICATION_ERROR"
+
+	// Delays between reconnect attempts, doubled after every failed attempt.
+	DefaultReconnectMinBackoff = time.Second
+	DefaultReconnectMaxBackoff = 30 * time.Second
+
+	// DefaultSubscriptionTimeout is how long the data of a subscription is waited for.
+	DefaultSubscriptionTimeout = 20 * time.Second
 )
 
 var (
@@ -50,24 +62,68 @@ type WSClientInterface interface {
 	Close() error
 
 	// Subscribe subscribes to a data type.
-	Subscribe(data WsSubRequestJson) (<-chan []byte, error)
+	Subscribe(data WsSubRequestJson) (<-chan SubscriptionResult, error)
+
+	// Stream subscribes to a data type, delivering every update until unsubscribed.
+	Stream(data WsSubRequestJson) (*Stream, error)
+
+	// Unsubscribe ends a streaming subscription.
+	Unsubscribe(subID int) error
+}
+
+// Stream is a long-lived subscription delivering the full payload on every update.
+type Stream struct {
+	ID   int
+	Data <-chan SubscriptionResult
 }
 
+// TokenRefresherFunc renews the session and returns the new session token.
+type TokenRefresherFunc func() (string, error)
+
 // WSClient is a WebSocket client for the Trade Republic API.
 type WSClient struct {
-	conn         *websocket.Conn
-	publisher    PublisherInterface
-	currentSubID uint
-	mu           sync.Mutex
-	closed       bool
-	ctx          context.Context
+	conn           *websocket.Conn
+	url            string
+	publisher      PublisherInterface
+	currentSubID   uint
+	mu             sync.Mutex
+	closed         bool
+	ctx            context.Context
+	locale         Locale
+	subscriptions  map[int]*subscription // Pending subscriptions keyed by their ID
+	reauthenticate TokenRefresherFunc
+	minBackoff     time.Duration
+	maxBackoff     time.Duration
+	timeout        time.Duration
 }
 
-// NewClient creates a new WebSocket client.
-func NewWSClient(publisher PublisherInterface, ctx context.Context) *WSClient {
+// subscription is a sent subscription request waiting for its data.
+type subscription struct {
+	request   WsSubRequestJson
+	renewed   bool
+	streaming bool
+	previous  string // Last payload of a streaming subscription, continue messages are applied to it
+	timer     *time.Timer
+}
+
+// NewClient creates a new WebSocket client requesting responses in the given locale.
+func NewWSClient(publisher PublisherInterface, ctx context.Context, locale Locale) *WSClient {
+	websocketURL := url.URL{Scheme: "wss", Host: WebsocketBaseHost, Path: "/"}
+
+	return NewWSClientWithURL(publisher, ctx, locale, websocketURL.String())
+}
+
+// NewWSClientWithURL creates a new WebSocket client connected to the given URL.
+func NewWSClientWithURL(publisher PublisherInterface, ctx context.Context, locale Locale, websocketURL string) *WSClient {
 	client := &WSClient{
-		publisher: publisher,
-		ctx:       ctx,
+		url:           websocketURL,
+		publisher:     publisher,
+		ctx:           ctx,
+		locale:        locale,
+		subscriptions: make(map[int]*subscription),
+		minBackoff:    DefaultReconnectMinBackoff,
+		maxBackoff:    DefaultReconnectMaxBackoff,
+		timeout:       DefaultSubscriptionTimeout,
 	}
 
 	err := client.Connect()
@@ -89,53 +145,226 @@ func (c *WSClient) Connect() error {
 		return nil
 	}
 
-	websocketURL := url.URL{Scheme: "wss", Host: WebsocketBaseHost, Path: "/"}
-	slog.Info("connecting to WebSocket", "url", websocketURL.String())
+	conn, err := c.dial()
+	if err != nil {
+		return err
+	}
+
+	c.conn = conn
+	c.closed = false
+
+	// Start goroutine to read messages
+	go c.readMessages(conn)
+
+	// Closing unblocks the reader, pending subscriptions then fail instead of waiting
+	go func() {
+		<-c.ctx.Done()
+
+		err := c.Close()
+		if err != nil {
+			slog.Error("error closing connection", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// dial connects to the WebSocket server and sends the connect handshake, the connection is returned
+// without being used by the client yet.
+func (c *WSClient) dial() (*websocket.Conn, error) {
+	slog.Info("connecting to WebSocket", "url", c.url)
 
 	// Create header with user agent
 	header := make(map[string][]string)
 	header["User-Agent"] = []string{HTTPUserAgent}
 
 	// Connect to the WebSocket server
-	conn, _, err := websocket.DefaultDialer.DialContext(c.ctx, websocketURL.String(), header)
+	conn, _, err := websocket.DefaultDialer.DialContext(c.ctx, c.url, header)
 	if err != nil {
-		return fmt.Errorf("could not connect to websocket: %w", err)
+		return nil, fmt.Errorf("could not connect to websocket: %w", err)
 	}
 
-	c.conn = conn
-	c.closed = false
 	data := WsConnectRequestJson{}
 
 	// Use default values from schema
 	_ = data.UnmarshalJSON([]byte("{}"))
 
+	if c.locale != "" {
+		data.Locale = string(c.locale)
+	}
+
 	// Marshal data to JSON
 	dataBytes, err := json.Marshal(data)
 	if err != nil {
-		return fmt.Errorf("could not marshal data: %w", err)
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("could not marshal data: %w", err)
 	}
 
 	payload := fmt.Sprintf("connect %s %s", WebhookVersion, dataBytes)
 
 	// Send connect message
-	if err = c.conn.WriteMessage(websocket.TextMessage, []byte(payload)); err != nil {
-		return fmt.Errorf("could not send connect message: %w", err)
+	if err = conn.WriteMessage(websocket.TextMessage, []byte(payload)); err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("could not send connect message: %w", err)
 	}
 
 	slog.Debug("sent connect message", "message", string(payload))
 
 	// Read the response
-	_, msg, err := c.conn.ReadMessage()
+	_, msg, err := conn.ReadMessage()
 	if err != nil {
-		return fmt.Errorf("could not read connect response: %w", err)
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("could not read connect response: %w", err)
 	}
 
 	slog.Debug("received connect response", "response", string(msg))
 
-	// Start goroutine to read messages
-	go c.readMessages()
+	return conn, nil
+}
 
-	return nil
+// SetReconnectBackoff sets the delay before the first reconnect attempt and the maximum delay the
+// doubling delays between further attempts are capped at.
+func (c *WSClient) SetReconnectBackoff(minBackoff, maxBackoff time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.minBackoff = minBackoff
+	c.maxBackoff = maxBackoff
+}
+
+// SetSubscriptionTimeout sets how long the data of a subscription is waited for before it fails with
+// ErrSubscriptionTimeout, zero waits forever. Streaming subscriptions only wait for their first payload.
+func (c *WSClient) SetSubscriptionTimeout(timeout time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.timeout = timeout
+}
+
+// reconnect replaces a broken connection, retrying with exponential backoff until it succeeds or the
+// context is done, and starts reading from the new connection. Subscriptions still waiting for their data
+// are sent again on the new connection, so the channels of their callers keep receiving. They fail with
+// ErrConnectionClosed when the client gives up.
+func (c *WSClient) reconnect(cause error) {
+	conn, err := c.retryDial(cause)
+	if err != nil {
+		slog.Error("giving up on websocket connection", "error", err)
+
+		c.failAll(ErrConnectionClosed)
+
+		return
+	}
+
+	go c.readMessages(conn)
+}
+
+// retryDial connects again with exponential backoff until it succeeds or the context is done.
+func (c *WSClient) retryDial(cause error) (*websocket.Conn, error) {
+	c.mu.Lock()
+	backoff := c.minBackoff
+	maxBackoff := c.maxBackoff
+
+	if c.conn != nil {
+		_ = c.conn.Close()
+	}
+
+	// Pending subscriptions are only timed again once they are replayed
+	for _, sub := range c.subscriptions {
+		if sub.timer != nil {
+			sub.timer.Stop()
+		}
+	}
+
+	c.mu.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		slog.Warn("websocket connection lost, reconnecting", "attempt", attempt, "backoff", backoff, "error", cause)
+
+		select {
+		case <-c.ctx.Done():
+			return nil, fmt.Errorf("could not reconnect: %w", c.ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		conn, err := c.redial()
+		if err == nil {
+			return conn, nil
+		}
+
+		if errors.Is(err, ErrConnectionClosed) {
+			return nil, err
+		}
+
+		cause = err
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// redial connects again and replays the pending subscriptions in the order they were made, the lock is
+// only held once the connection is established.
+func (c *WSClient) redial() (*websocket.Conn, error) {
+	if c.isClosed() {
+		return nil, ErrConnectionClosed
+	}
+
+	conn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Closed while dialing
+	if c.closed {
+		_ = conn.Close()
+
+		return nil, ErrConnectionClosed
+	}
+
+	c.conn = conn
+
+	subIDs := make([]int, 0, len(c.subscriptions))
+	for subID := range c.subscriptions {
+		subIDs = append(subIDs, subID)
+	}
+
+	slices.Sort(subIDs)
+
+	for _, subID := range subIDs {
+		sub := c.subscriptions[subID]
+
+		err = c.sendSubscription(subID, sub.request)
+		if err != nil {
+			return nil, fmt.Errorf("could not replay subscription %d: %w", subID, err)
+		}
+
+		c.startTimer(subID, sub)
+	}
+
+	slog.Info("websocket reconnected", "replayed_subscriptions", len(subIDs))
+
+	return conn, nil
+}
+
+// isClosed reports whether the client was closed on purpose.
+func (c *WSClient) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.closed
+}
+
+// SetTokenRefresher sets the function renewing the session when a subscription is rejected as
+// unauthenticated, the subscription is then sent again with the new token.
+func (c *WSClient) SetTokenRefresher(refresher TokenRefresherFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reauthenticate = refresher
 }
 
 // Close closes the WebSocket connection.
@@ -143,7 +372,7 @@ func (c *WSClient) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.conn == nil {
+	if c.conn == nil || c.closed {
 		return nil
 	}
 
@@ -157,45 +386,169 @@ func (c *WSClient) Close() error {
 	return nil
 }
 
-// subscribe subscribes to a data type.
-func (c *WSClient) Subscribe(data WsSubRequestJson) (<-chan []byte, error) {
+// Subscribe subscribes to a data type, the channel receives the data or the error once and is closed.
+func (c *WSClient) Subscribe(data WsSubRequestJson) (<-chan SubscriptionResult, error) {
+	_, ch, err := c.subscribe(data, false)
+
+	return ch, err
+}
+
+// Stream subscribes to a data type, the channel receives the full payload on every update until
+// Unsubscribe is called or the subscription fails.
+func (c *WSClient) Stream(data WsSubRequestJson) (*Stream, error) {
+	subID, ch, err := c.subscribe(data, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Stream{ID: subID, Data: ch}, nil
+}
+
+// Unsubscribe ends a streaming subscription and closes its channel, updates queued before can still be received.
+func (c *WSClient) Unsubscribe(subID int) error {
+	if !c.claim(subID) {
+		return fmt.Errorf("unknown subscription %d", subID)
+	}
+
+	c.unsubscribe(subID)
+	c.publisher.Close(strconv.Itoa(subID))
+
+	return nil
+}
+
+func (c *WSClient) subscribe(data WsSubRequestJson, streaming bool) (int, <-chan SubscriptionResult, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if c.conn == nil {
-		return nil, ErrNotConnected
+		return 0, nil, ErrNotConnected
 	}
 
 	if c.closed {
-		return nil, ErrConnectionClosed
+		return 0, nil, ErrConnectionClosed
 	}
 
 	c.currentSubID++
-	subID := strconv.FormatUint(uint64(c.currentSubID), 10)
+	subID := int(c.currentSubID)
+	ch := c.publisher.Subscribe(strconv.Itoa(subID))
+
+	err := c.sendSubscription(subID, data)
+	if err != nil {
+		c.publisher.Close(strconv.Itoa(subID))
+
+		return 0, nil, err
+	}
+
+	sub := &subscription{request: data, streaming: streaming}
+
+	c.startTimer(subID, sub)
+	c.subscriptions[subID] = sub
+
+	return subID, ch, nil
+}
+
+// startTimer fails the subscription when no data arrives in time after it was sent, replacing the timer
+// of a previous send. The caller has to hold the lock.
+func (c *WSClient) startTimer(subID int, sub *subscription) {
+	if sub.timer != nil {
+		sub.timer.Stop()
+	}
 
+	if c.timeout <= 0 {
+		return
+	}
+
+	timeout := c.timeout
+
+	sub.timer = time.AfterFunc(timeout, func() {
+		c.fail(subID, fmt.Errorf("%w: no data after %s", ErrSubscriptionTimeout, timeout))
+	})
+}
+
+// sendSubscription sends the subscription message, the caller has to hold the lock.
+func (c *WSClient) sendSubscription(subID int, data WsSubRequestJson) error {
 	// Marshal data to JSON
 	dataBytes, err := json.Marshal(data)
 	if err != nil {
-		return nil, fmt.Errorf("could not marshal data: %w", err)
+		return fmt.Errorf("could not marshal data: %w", err)
 	}
 
-	ch := c.publisher.Subscribe(subID)
-
 	// Create subscription message
-	msg := fmt.Sprintf("%s %s %s", MsgTypeSub, subID, string(dataBytes))
+	msg := fmt.Sprintf("%s %d %s", MsgTypeSub, subID, string(dataBytes))
 
 	// Send subscription message
 	if err = c.conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
-		return nil, fmt.Errorf("could not send subscription message: %w", err)
+		return fmt.Errorf("could not send subscription message: %w", err)
 	}
 
 	slog.Debug("sent subscription message", "message", msg)
 
-	return ch, nil
+	return nil
 }
 
-// readMessages reads messages from the WebSocket and sends them to the channel.
-func (c *WSClient) readMessages() {
+// renew sends a subscription rejected as unauthenticated again with a new session, the subscription fails
+// with the rejection when it cannot be renewed. It runs on its own goroutine, so the reader keeps delivering
+// the other subscriptions while the session is refreshed.
+func (c *WSClient) renew(subID int, rejection error) {
+	err := c.resubscribe(subID)
+	if err == nil {
+		slog.Info("session expired, subscription renewed", "id", subID)
+
+		return
+	}
+
+	slog.Error("could not renew subscription", "id", subID, "error", err)
+
+	c.fail(subID, rejection)
+}
+
+// resubscribe renews the session and sends the subscription again with the new token.
+// Subscriptions are only renewed once, a second rejection is reported as an error.
+func (c *WSClient) resubscribe(subID int) error {
+	c.mu.Lock()
+	refresher := c.reauthenticate
+	sub, found := c.subscriptions[subID]
+
+	if refresher == nil || !found {
+		c.mu.Unlock()
+
+		return ErrAuthRequired
+	}
+
+	if sub.renewed {
+		c.mu.Unlock()
+
+		return fmt.Errorf("%w: subscription %d was already renewed", ErrAuthRequired, subID)
+	}
+
+	sub.renewed = true
+	c.mu.Unlock()
+
+	token, err := refresher()
+	if err != nil {
+		return fmt.Errorf("could not refresh session: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil || c.closed {
+		return ErrConnectionClosed
+	}
+
+	// Answered while the session was refreshed
+	if c.subscriptions[subID] != sub {
+		return fmt.Errorf("subscription %d is no longer pending", subID)
+	}
+
+	sub.request.Token = token
+
+	return c.sendSubscription(subID, sub.request)
+}
+
+// readMessages reads messages from the connection and sends them to the channels. A broken connection is
+// replaced by a new reader, subscriptions still pending when the client is closed fail with ErrConnectionClosed.
+func (c *WSClient) readMessages(conn *websocket.Conn) {
 	for {
 		select {
 		case <-c.ctx.Done():
@@ -206,14 +559,22 @@ func (c *WSClient) readMessages() {
 				slog.Error("error closing connection", "error", err)
 			}
 
+			c.failAll(ErrConnectionClosed)
+
 			return
 		default:
 			// Read message
-			_, msg, err := c.conn.ReadMessage()
+			_, msg, err := conn.ReadMessage()
 			if err != nil {
-				slog.Error("error reading message", "error", err)
+				if c.isClosed() || c.ctx.Err() != nil {
+					c.failAll(ErrConnectionClosed)
 
-				continue
+					return
+				}
+
+				go c.reconnect(err)
+
+				return
 			}
 
 			// Parse message
@@ -224,27 +585,58 @@ func (c *WSClient) readMessages() {
 				continue
 			}
 
+			subID := strconv.Itoa(message.ID)
+
 			// Handle message based on state
 			switch message.State {
 			case StateData:
-				c.unsubscribe(message.ID)
+				if payload, streaming := c.update(message); streaming {
+					c.publisher.Publish(SubscriptionResult{Data: []byte(payload)}, subID)
 
-				subID := strconv.FormatInt(int64(message.ID), 10)
+					continue
+				}
 
-				c.publisher.Publish([]byte(message.Data), subID)
+				// Timed out subscriptions were already answered
+				if !c.claim(message.ID) {
+					slog.Debug("received data of finished subscription", "id", message.ID)
+
+					continue
+				}
+
+				c.unsubscribe(message.ID)
+
+				c.publisher.Publish(SubscriptionResult{Data: []byte(message.Data)}, subID)
 				c.publisher.Close(subID)
 
 				continue
 
 			case StateContinue:
-				// Continue reading
-				slog.Debug("received continue message", "message", string(msg))
+				payload, streaming := c.update(message)
+				if !streaming {
+					slog.Debug("received continue message", "message", string(msg))
+
+					continue
+				}
+
+				if payload != "" {
+					c.publisher.Publish(SubscriptionResult{Data: []byte(payload)}, subID)
+				}
 
 				continue
 
 			case StateError:
+				subErr := parseSubscriptionError(message.Data)
+
+				if errors.Is(subErr, ErrAuthRequired) {
+					go c.renew(message.ID, subErr)
+
+					continue
+				}
+
 				slog.Error("received error message", "message", string(msg))
 
+				c.fail(message.ID, subErr)
+
 				continue
 			}
 		}
@@ -273,6 +665,90 @@ func (c *WSClient) unsubscribe(subID int) {
 	slog.Debug("sent unsubscribe message", "message", msg)
 }
 
+// update keeps the payload of a streaming subscription, applying the delta of continue messages to the
+// previous payload. It reports whether the message belongs to a streaming subscription, an empty payload
+// is returned when the delta could not be applied.
+func (c *WSClient) update(message WsResponseJson) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sub, found := c.subscriptions[message.ID]
+	if !found || !sub.streaming {
+		return "", false
+	}
+
+	payload := message.Data
+
+	if sub.timer != nil {
+		sub.timer.Stop()
+	}
+
+	if message.State == StateContinue {
+		var err error
+
+		payload, err = ApplyDelta(sub.previous, message.Data)
+		if err != nil {
+			slog.Error("could not apply update", "id", message.ID, "error", err)
+
+			return "", true
+		}
+	}
+
+	sub.previous = payload
+
+	return payload, true
+}
+
+// claim drops a subscription that does not need to be renewed anymore, reporting whether it was still
+// pending. Only the caller claiming a subscription answers it.
+func (c *WSClient) claim(subID int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sub, found := c.subscriptions[subID]
+	if !found {
+		return false
+	}
+
+	if sub.timer != nil {
+		sub.timer.Stop()
+	}
+
+	delete(c.subscriptions, subID)
+
+	return true
+}
+
+// fail answers a pending subscription with the error and closes its channel.
+func (c *WSClient) fail(subID int, err error) {
+	if !c.claim(subID) {
+		return
+	}
+
+	c.unsubscribe(subID)
+
+	topic := strconv.Itoa(subID)
+
+	c.publisher.Publish(SubscriptionResult{Err: err}, topic)
+	c.publisher.Close(topic)
+}
+
+// failAll answers all pending subscriptions with the error.
+func (c *WSClient) failAll(err error) {
+	c.mu.Lock()
+
+	subIDs := make([]int, 0, len(c.subscriptions))
+	for subID := range c.subscriptions {
+		subIDs = append(subIDs, subID)
+	}
+
+	c.mu.Unlock()
+
+	for _, subID := range subIDs {
+		go c.fail(subID, err)
+	}
+}
+
 // parseMessage parses a message from the WebSocket.
 func parseMessage(data []byte) (WsResponseJson, error) {
 	var msg WsResponseJson
@@ -67,11 +67,26 @@ func (mr *MockWSClientInterfaceMockRecorder) Connect() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connect", reflect.TypeOf((*MockWSClientInterface)(nil).Connect))
 }
 
+// Stream mocks base method.
+func (m *MockWSClientInterface) Stream(data WsSubRequestJson) (*Stream, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", data)
+	ret0, _ := ret[0].(*Stream)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockWSClientInterfaceMockRecorder) Stream(data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockWSClientInterface)(nil).Stream), data)
+}
+
 // Subscribe mocks base method.
-func (m *MockWSClientInterface) Subscribe(data WsSubRequestJson) (<-chan []byte, error) {
+func (m *MockWSClientInterface) Subscribe(data WsSubRequestJson) (<-chan SubscriptionResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Subscribe", data)
-	ret0, _ := ret[0].(<-chan []byte)
+	ret0, _ := ret[0].(<-chan SubscriptionResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -81,3 +96,17 @@ func (mr *MockWSClientInterfaceMockRecorder) Subscribe(data any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockWSClientInterface)(nil).Subscribe), data)
 }
+
+// Unsubscribe mocks base method.
+func (m *MockWSClientInterface) Unsubscribe(subID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", subID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockWSClientInterfaceMockRecorder) Unsubscribe(subID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockWSClientInterface)(nil).Unsubscribe), subID)
+}
@@ -0,0 +1,306 @@
+package traderepublic_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dhojayev/traderepublic-portfolio-downloader/v2/pkg/traderepublic"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWSServer accepts websocket connections, answers the connect handshake and hands the connection
+// with its number, starting at 1, to the script.
+func fakeWSServer(t *testing.T, script func(conn *websocket.Conn, connection int)) string {
+	t.Helper()
+
+	var connections atomic.Int32
+
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		_, msg, err := conn.ReadMessage()
+		if err != nil || !strings.HasPrefix(string(msg), "connect "+traderepublic.WebhookVersion) {
+			return
+		}
+
+		err = conn.WriteMessage(websocket.TextMessage, []byte("connected"))
+		if err != nil {
+			return
+		}
+
+		script(conn, int(connections.Add(1)))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// readSub reads the next message, expected to be a subscription.
+func readSub(conn *websocket.Conn) (string, bool) {
+	_, msg, err := conn.ReadMessage()
+	if err != nil || !strings.HasPrefix(string(msg), traderepublic.MsgTypeSub+" ") {
+		return "", false
+	}
+
+	return string(msg), true
+}
+
+func TestWSClient_Reconnect(t *testing.T) {
+	t.Parallel()
+
+	replayed := make(chan string, 1)
+
+	serverURL := fakeWSServer(t, func(conn *websocket.Conn, connection int) {
+		msg, ok := readSub(conn)
+		if !ok {
+			return
+		}
+
+		// The first connection drops before answering the subscription
+		if connection == 1 {
+			return
+		}
+
+		replayed <- msg
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`1 A {"id":"instrument"}`))
+
+		// Wait for the unsubscribe before closing
+		_, _, _ = conn.ReadMessage()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := traderepublic.NewWSClientWithURL(traderepublic.NewPublisher(), ctx, traderepublic.LocaleEN, serverURL)
+	require.NotNil(t, client)
+
+	client.SetReconnectBackoff(10*time.Millisecond, 50*time.Millisecond)
+
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	ch, err := client.Subscribe(traderepublic.WsSubRequestJson{Type: "instrument", Token: "token"})
+	require.NoError(t, err)
+
+	select {
+	case result := <-ch:
+		require.NoError(t, result.Err)
+		assert.JSONEq(t, `{"id":"instrument"}`, string(result.Data))
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no data received after reconnect")
+	}
+
+	assert.Contains(t, <-replayed, `sub 1 {`)
+}
+
+func TestWSClient_Stream(t *testing.T) {
+	t.Parallel()
+
+	unsubscribed := make(chan string, 1)
+
+	serverURL := fakeWSServer(t, func(conn *websocket.Conn, _ int) {
+		if _, ok := readSub(conn); !ok {
+			return
+		}
+
+		for _, msg := range []string{
+			`1 A {"bid":{"price":101.5},"open":100}`,
+			"1 C =16\t-5\t+102.25\t=13",
+			"1 C =16\t-6\t+99\t=13",
+		} {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				return
+			}
+		}
+
+		_, msg, err := conn.ReadMessage()
+		if err == nil {
+			unsubscribed <- string(msg)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := traderepublic.NewWSClientWithURL(traderepublic.NewPublisher(), ctx, traderepublic.LocaleEN, serverURL)
+	require.NotNil(t, client)
+
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	stream, err := client.Stream(traderepublic.WsSubRequestJson{Type: "ticker", Token: "token"})
+	require.NoError(t, err)
+
+	expected := []string{
+		`{"bid":{"price":101.5},"open":100}`,
+		`{"bid":{"price":102.25},"open":100}`,
+		`{"bid":{"price":99},"open":100}`,
+	}
+
+	for _, snapshot := range expected {
+		select {
+		case result := <-stream.Data:
+			require.NoError(t, result.Err)
+			assert.Equal(t, snapshot, string(result.Data))
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "no update received")
+		}
+	}
+
+	require.NoError(t, client.Unsubscribe(stream.ID))
+	assert.Equal(t, "unsub 1", <-unsubscribed)
+
+	// The channel is closed once unsubscribed
+	_, open := <-stream.Data
+	assert.False(t, open)
+
+	assert.Error(t, client.Unsubscribe(stream.ID))
+}
+
+func TestWSClient_ReconnectRestartsTimeout(t *testing.T) {
+	t.Parallel()
+
+	serverURL := fakeWSServer(t, func(conn *websocket.Conn, connection int) {
+		if _, ok := readSub(conn); !ok {
+			return
+		}
+
+		// The first connection drops before answering the subscription
+		if connection == 1 {
+			return
+		}
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`1 A {"id":"instrument"}`))
+
+		// Wait for the unsubscribe before closing
+		_, _, _ = conn.ReadMessage()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := traderepublic.NewWSClientWithURL(traderepublic.NewPublisher(), ctx, traderepublic.LocaleEN, serverURL)
+	require.NotNil(t, client)
+
+	// The backoff alone outlasts the timeout, which only counts once the subscription is replayed
+	client.SetReconnectBackoff(300*time.Millisecond, 300*time.Millisecond)
+	client.SetSubscriptionTimeout(200 * time.Millisecond)
+
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	ch, err := client.Subscribe(traderepublic.WsSubRequestJson{Type: "instrument", Token: "token"})
+	require.NoError(t, err)
+
+	select {
+	case result := <-ch:
+		require.NoError(t, result.Err)
+		assert.JSONEq(t, `{"id":"instrument"}`, string(result.Data))
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no data received after reconnect")
+	}
+}
+
+func TestWSClient_SubscriptionErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		reply    string
+		expected error
+	}{
+		{
+			name:     "Not found",
+			reply:    `1 E {"errors":[{"errorCode":"NOT_FOUND","errorMessage":"Unknown instrument"}]}`,
+			expected: traderepublic.ErrNotFound,
+		},
+		{
+			name:     "Rate limited",
+			reply:    `1 E {"errors":[{"errorCode":"TOO_MANY_REQUESTS","errorMessage":"Slow down"}]}`,
+			expected: traderepublic.ErrRateLimited,
+		},
+		{
+			name:     "Authentication without refresher",
+			reply:    `1 E {"errors":[{"errorCode":"AUTHENTICATION_ERROR","errorMessage":"Unauthorized"}]}`,
+			expected: traderepublic.ErrAuthRequired,
+		},
+		{
+			name:     "Unknown error",
+			reply:    `1 E {"errors":[{"errorCode":"BAD_SUBSCRIPTION_TYPE"}]}`,
+			expected: traderepublic.ErrSubscriptionFailed,
+		},
+		{
+			name:     "Unparsable error",
+			reply:    `1 E oops`,
+			expected: traderepublic.ErrSubscriptionFailed,
+		},
+		{
+			name:     "No reply",
+			expected: traderepublic.ErrSubscriptionTimeout,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			serverURL := fakeWSServer(t, func(conn *websocket.Conn, _ int) {
+				if _, ok := readSub(conn); !ok {
+					return
+				}
+
+				if testCase.reply != "" {
+					_ = conn.WriteMessage(websocket.TextMessage, []byte(testCase.reply))
+				}
+
+				// Wait for the unsubscribe before closing
+				_, _, _ = conn.ReadMessage()
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			client := traderepublic.NewWSClientWithURL(traderepublic.NewPublisher(), ctx, traderepublic.LocaleEN, serverURL)
+			require.NotNil(t, client)
+
+			client.SetSubscriptionTimeout(100 * time.Millisecond)
+
+			t.Cleanup(func() {
+				_ = client.Close()
+			})
+
+			ch, err := client.Subscribe(traderepublic.WsSubRequestJson{Type: "instrument", Token: "token"})
+			require.NoError(t, err)
+
+			select {
+			case result := <-ch:
+				assert.ErrorIs(t, result.Err, testCase.expected)
+				assert.Nil(t, result.Data)
+			case <-time.After(5 * time.Second):
+				require.FailNow(t, "subscription not answered")
+			}
+
+			_, open := <-ch
+			assert.False(t, open)
+		})
+	}
+}